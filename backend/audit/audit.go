@@ -0,0 +1,177 @@
+package audit
+
+import (
+	"backend/database"
+	"backend/models"
+	"encoding/json"
+	"fmt"
+	"log"
+	"reflect"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Actions written to the audit log
+const (
+	ActionAdminSignup          = "admin.signup"
+	ActionAdminBootstrap       = "admin.bootstrap"
+	ActionAdminUpdate          = "admin.update"
+	ActionAdminDisable         = "admin.disable"
+	ActionAdminEnable          = "admin.enable"
+	ActionAdminDelete          = "admin.delete"
+	ActionAdminPasswordReset   = "admin.password_reset"
+	ActionProfileUpdate        = "admin.profile_update"
+	ActionInviteCreate         = "invite.create"
+	ActionInviteRevoke         = "invite.revoke"
+	ActionPasswordReset        = "password.reset"
+	ActionPasswordChange       = "password.change"
+	ActionMFAEnable            = "mfa.enable"
+	ActionMFADisable           = "mfa.disable"
+	ActionLoginLockout         = "auth.lockout"
+	ActionAPIKeyCreate         = "api_key.create"
+	ActionAPIKeyRevoke         = "api_key.revoke"
+	ActionBlogCreate           = "blog.create"
+	ActionBlogUpdate           = "blog.update"
+	ActionBlogDelete           = "blog.delete"
+	ActionBlogStatus           = "blog.status"
+	ActionBlogSchedule         = "blog.schedule"
+	ActionBlogUnschedule       = "blog.unschedule"
+	ActionBlogPublishScheduled = "blog.publish_scheduled"
+	ActionBlogRestore          = "blog.restore"
+	ActionTagCreate            = "tag.create"
+	ActionTagUpdate            = "tag.update"
+	ActionTagDelete            = "tag.delete"
+	ActionCategoryCreate       = "category.create"
+	ActionCategoryUpdate       = "category.update"
+	ActionCategoryDelete       = "category.delete"
+	ActionCommentModerate      = "comment.moderate"
+	ActionCommentDelete        = "comment.delete"
+)
+
+// Fields never written to the log, in case a model starts serialising them
+var redactedFields = map[string]bool{
+	"password":    true,
+	"totp_secret": true,
+}
+
+// Entry describes a single mutating action
+type Entry struct {
+	Action     string
+	TargetType string
+	TargetID   interface{}
+	Before     interface{}
+	After      interface{}
+	// ActorID overrides the admin taken from the request context, for
+	// actions such as signup that happen before authentication
+	ActorID *uint
+}
+
+// Record writes entry to the audit log. Failures are logged rather than
+// returned so auditing never breaks the action being audited.
+func Record(c *gin.Context, entry Entry) {
+	if err := RecordTx(database.DB, c, entry); err != nil {
+		log.Printf("Failed to write audit event %s: %v", entry.Action, err)
+	}
+}
+
+// RecordTx writes entry using tx, so the event commits or rolls back
+// together with the change it describes
+func RecordTx(tx *gorm.DB, c *gin.Context, entry Entry) error {
+	before, err := snapshot(entry.Before)
+	if err != nil {
+		return err
+	}
+	after, err := snapshot(entry.After)
+	if err != nil {
+		return err
+	}
+
+	event := models.AuditEvent{
+		ActorAdminID: entry.ActorID,
+		Action:       entry.Action,
+		TargetType:   entry.TargetType,
+		Before:       encode(before),
+		After:        encode(after),
+		Changes:      encode(diff(before, after)),
+	}
+	if entry.TargetID != nil {
+		event.TargetID = fmt.Sprint(entry.TargetID)
+	}
+
+	if c != nil {
+		if event.ActorAdminID == nil {
+			if value, ok := c.Get("adminID"); ok {
+				if adminID, ok := value.(uint); ok {
+					event.ActorAdminID = &adminID
+				}
+			}
+		}
+		event.IPAddress = c.ClientIP()
+		event.UserAgent = c.Request.UserAgent()
+		if len(event.UserAgent) > 255 {
+			event.UserAgent = event.UserAgent[:255]
+		}
+	}
+
+	return tx.Create(&event).Error
+}
+
+// snapshot converts a value to a flat JSON object with redacted fields removed
+func snapshot(value interface{}) (map[string]interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+	if v := reflect.ValueOf(value); v.Kind() == reflect.Ptr && v.IsNil() {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	for key := range fields {
+		if redactedFields[key] {
+			delete(fields, key)
+		}
+	}
+	return fields, nil
+}
+
+// diff returns {"field": {"from": x, "to": y}} for every field that differs
+func diff(before, after map[string]interface{}) map[string]interface{} {
+	if before == nil && after == nil {
+		return nil
+	}
+
+	changes := make(map[string]interface{})
+	for key, old := range before {
+		if updated, ok := after[key]; !ok || !reflect.DeepEqual(old, updated) {
+			changes[key] = map[string]interface{}{"from": old, "to": after[key]}
+		}
+	}
+	for key, updated := range after {
+		if _, ok := before[key]; !ok {
+			changes[key] = map[string]interface{}{"from": nil, "to": updated}
+		}
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+	return changes
+}
+
+func encode(value map[string]interface{}) models.JSON {
+	if value == nil {
+		return nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil
+	}
+	return models.JSON(data)
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"log"
 	"os"
+	"strings"
 
 	"github.com/joho/godotenv"
 )
@@ -16,7 +17,7 @@ func LoadEnv() {
 	// Validate required environment variables
 	requiredVars := []string{
 		"DB_HOST", "DB_PORT", "DB_USER",
-		"DB_PASSWORD", "DB_NAME", "JWT_SECRET",
+		"DB_PASSWORD", "DB_NAME",
 	}
 
 	for _, envVar := range requiredVars {
@@ -25,3 +26,19 @@ func LoadEnv() {
 		}
 	}
 }
+
+// SiteURL is the public address of the website that shows the posts,
+// from SITE_URL. Absolute links are only ever built from configuration,
+// never from request headers a client controls; it is empty when unset.
+func SiteURL() string {
+	return strings.TrimRight(os.Getenv("SITE_URL"), "/")
+}
+
+// PublicURL is the public address of this API, from PUBLIC_URL or else
+// SITE_URL when both are served from one host
+func PublicURL() string {
+	if base := os.Getenv("PUBLIC_URL"); base != "" {
+		return strings.TrimRight(base, "/")
+	}
+	return SiteURL()
+}
@@ -1,44 +1,186 @@
 package controllers
 
 import (
+	"backend/audit"
 	"backend/database"
 	"backend/models"
 	"backend/utils"
+	"crypto/subtle"
+	"errors"
 	"net/http"
+	"os"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+var (
+	errInvalidInvite     = errors.New("invalid or expired invite")
+	errEmailRegistered   = errors.New("email already registered")
+	errBootstrapDisabled = errors.New("bootstrap is not available")
+)
+
+// AdminSignup registers a new admin from a single-use invite token.
+// The email and role come from the invite, not from the request.
 func AdminSignup(c *gin.Context) {
-	var admin models.Admin
-	if err := c.ShouldBindJSON(&admin); err != nil {
+	var input models.SignupRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
 		return
 	}
 
-	// Check if email already exists
-	var existing models.Admin
-	if err := database.DB.Where("email = ?", admin.Email).First(&existing).Error; err == nil {
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(input.Password), bcrypt.DefaultCost)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not hash password"})
+		return
+	}
+
+	var admin models.Admin
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		var invite models.AdminInvite
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("token_hash = ?", utils.HashToken(input.Token)).
+			First(&invite).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errInvalidInvite
+			}
+			return err
+		}
+
+		now := time.Now()
+		if !invite.IsPending(now) {
+			return errInvalidInvite
+		}
+
+		email := strings.ToLower(strings.TrimSpace(invite.Email))
+		var existing int64
+		if err := tx.Model(&models.Admin{}).Where("LOWER(email) = ?", email).Count(&existing).Error; err != nil {
+			return err
+		}
+		if existing > 0 {
+			return errEmailRegistered
+		}
+
+		admin = models.Admin{
+			Username: input.Username,
+			Email:    email,
+			Password: string(hashedPassword),
+			Role:     invite.Role,
+		}
+		if err := tx.Create(&admin).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&invite).Update("accepted_at", now).Error; err != nil {
+			return err
+		}
+
+		return audit.RecordTx(tx, c, audit.Entry{
+			Action:     audit.ActionAdminSignup,
+			TargetType: "admin",
+			TargetID:   admin.ID,
+			After:      admin,
+			ActorID:    &admin.ID,
+		})
+	})
+
+	switch {
+	case errors.Is(err, errInvalidInvite):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or expired invite"})
+		return
+	case errors.Is(err, errEmailRegistered):
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Email already registered"})
 		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to register admin", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Admin registered successfully",
+		"admin": gin.H{
+			"id":    admin.ID,
+			"email": admin.Email,
+			"role":  admin.Role,
+		},
+	})
+}
+
+// AdminBootstrap creates the first owner account. It only works while
+// no admin exists and the caller knows ADMIN_BOOTSTRAP_TOKEN.
+func AdminBootstrap(c *gin.Context) {
+	var input models.BootstrapRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		return
 	}
 
-	// Hash password
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(admin.Password), bcrypt.DefaultCost)
+	expected := os.Getenv("ADMIN_BOOTSTRAP_TOKEN")
+	if expected == "" || subtle.ConstantTimeCompare([]byte(expected), []byte(input.Token)) != 1 {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Bootstrap is not available"})
+		return
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(input.Password), bcrypt.DefaultCost)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not hash password"})
 		return
 	}
-	admin.Password = string(hashedPassword)
 
-	// Save admin
-	if err := database.DB.Create(&admin).Error; err != nil {
+	admin := models.Admin{
+		Username: input.Username,
+		Email:    strings.ToLower(strings.TrimSpace(input.Email)),
+		Password: string(hashedPassword),
+		Role:     models.RoleOwner,
+	}
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		// Serialise concurrent bootstrap attempts
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext('admin_bootstrap'))").Error; err != nil {
+			return err
+		}
+
+		var count int64
+		if err := tx.Model(&models.Admin{}).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return errBootstrapDisabled
+		}
+
+		if err := tx.Create(&admin).Error; err != nil {
+			return err
+		}
+
+		return audit.RecordTx(tx, c, audit.Entry{
+			Action:     audit.ActionAdminBootstrap,
+			TargetType: "admin",
+			TargetID:   admin.ID,
+			After:      admin,
+			ActorID:    &admin.ID,
+		})
+	})
+
+	switch {
+	case errors.Is(err, errBootstrapDisabled):
+		c.JSON(http.StatusForbidden, gin.H{"error": "Bootstrap is not available"})
+		return
+	case err != nil:
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to register admin", "details": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{"message": "Admin registered successfully"})
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Owner account created successfully",
+		"admin": gin.H{
+			"id":    admin.ID,
+			"email": admin.Email,
+			"role":  admin.Role,
+		},
+	})
 }
 
 func AdminLogin(c *gin.Context) {
@@ -48,31 +190,67 @@ func AdminLogin(c *gin.Context) {
 		return
 	}
 
-	// Find admin by email
+	email := strings.ToLower(strings.TrimSpace(input.Email))
+
+	// Count the attempt up front and refuse while this IP or account is
+	// backing off
+	keys := loginKeys(c, emailKey(email))
+	if !beginLoginAttempt(c, keys) {
+		return
+	}
+
+	// Find admin by email; accounts created before emails were normalised
+	// may still be stored mixed-case
 	var admin models.Admin
-	if err := database.DB.Where("email = ?", input.Email).First(&admin).Error; err != nil {
+	if err := database.DB.Where("LOWER(email) = ?", email).First(&admin).Error; err != nil {
+		recordLoginFailure(c, keys, email)
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid credentials"})
 		return
 	}
 
 	// Compare password
 	if err := bcrypt.CompareHashAndPassword([]byte(admin.Password), []byte(input.Password)); err != nil {
+		recordLoginFailure(c, keys, email)
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid credentials"})
 		return
 	}
+	resetLoginThrottle(c, keys)
+
+	if admin.IsDisabled() {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Account is disabled"})
+		return
+	}
+
+	// Accounts with two-factor authentication get a challenge token that
+	// only a valid code can exchange for a session
+	if admin.MFAEnabled() {
+		mfaToken, err := utils.GenerateMFAToken(admin.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not generate token"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"mfa_required": true,
+			"mfa_token":    mfaToken,
+		})
+		return
+	}
 
-	// Generate JWT token (admin.ID is now uint)
-	token, err := utils.GenerateToken(admin.ID)
+	completeLogin(c, admin)
+}
+
+// completeLogin starts a session and responds with the token pair
+func completeLogin(c *gin.Context, admin models.Admin) {
+	response, err := startSession(c, admin)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not generate token"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"token": token,
-		"admin": gin.H{
-			"id":    admin.ID,
-			"email": admin.Email,
-		},
-	})
+	response["admin"] = gin.H{
+		"id":    admin.ID,
+		"email": admin.Email,
+		"role":  admin.Role,
+	}
+	c.JSON(http.StatusOK, response)
 }
@@ -0,0 +1,189 @@
+package controllers
+
+import (
+	"backend/audit"
+	"backend/database"
+	"backend/models"
+	"backend/utils"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const apiKeyPrefix = "slk_"
+
+// CreateAPIKey mints a named, scoped API key for the signed-in admin.
+// The plain key is only returned in this response.
+func CreateAPIKey(c *gin.Context) {
+	admin, ok := currentAdmin(c)
+	if !ok {
+		return
+	}
+
+	var input models.CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		return
+	}
+
+	scopes := make([]string, 0, len(input.Scopes))
+	seen := make(map[string]bool)
+	for _, scope := range input.Scopes {
+		scope = strings.TrimSpace(scope)
+		if !models.IsValidScope(scope) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown scope: " + scope})
+			return
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			scopes = append(scopes, scope)
+		}
+	}
+
+	// A key can't be granted a scope none of whose permissions the
+	// admin's role has
+	for _, scope := range scopes {
+		granted := false
+		for _, permission := range admin.Role.Permissions() {
+			if models.ScopesGrant([]string{scope}, permission) {
+				granted = true
+				break
+			}
+		}
+		if !granted {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Your role cannot grant scope " + scope})
+			return
+		}
+	}
+
+	prefixBytes := make([]byte, 4)
+	if _, err := rand.Read(prefixBytes); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not generate key"})
+		return
+	}
+	secret, err := utils.GenerateOpaqueToken()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not generate key"})
+		return
+	}
+	prefix := apiKeyPrefix + hex.EncodeToString(prefixBytes)
+	key := prefix + "_" + secret
+
+	apiKey := models.APIKey{
+		AdminID: admin.ID,
+		Name:    strings.TrimSpace(input.Name),
+		Prefix:  prefix,
+		KeyHash: utils.HashToken(key),
+		Scopes:  strings.Join(scopes, " "),
+	}
+	if input.ExpiresInDays > 0 {
+		expiresAt := time.Now().AddDate(0, 0, input.ExpiresInDays)
+		apiKey.ExpiresAt = &expiresAt
+	}
+
+	if err := database.DB.Create(&apiKey).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create API key"})
+		return
+	}
+
+	audit.Record(c, audit.Entry{
+		Action:     audit.ActionAPIKeyCreate,
+		TargetType: "api_key",
+		TargetID:   apiKey.ID,
+		After:      apiKeyResponse(apiKey),
+	})
+
+	response := apiKeyResponse(apiKey)
+	response["key"] = key
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "API key created. Store it now, it will not be shown again",
+		"api_key": response,
+	})
+}
+
+// ListAPIKeys returns the signed-in admin's API keys
+func ListAPIKeys(c *gin.Context) {
+	adminID, err := getAdminID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Admin authentication required"})
+		return
+	}
+
+	var keys []models.APIKey
+	if err := database.DB.Where("admin_id = ?", adminID).Order("created_at DESC").Find(&keys).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch API keys"})
+		return
+	}
+
+	data := make([]gin.H, 0, len(keys))
+	for _, key := range keys {
+		data = append(data, apiKeyResponse(key))
+	}
+	c.JSON(http.StatusOK, data)
+}
+
+// RevokeAPIKey revokes one of the admin's keys. Owners may revoke anyone's.
+func RevokeAPIKey(c *gin.Context) {
+	adminID, err := getAdminID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Admin authentication required"})
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var apiKey models.APIKey
+	if err := database.DB.First(&apiKey, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "API key not found"})
+		return
+	}
+
+	if apiKey.AdminID != adminID && !hasPermission(c, models.PermAdminManage) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "API key not found"})
+		return
+	}
+
+	if apiKey.RevokedAt != nil {
+		c.JSON(http.StatusOK, gin.H{"message": "API key already revoked"})
+		return
+	}
+
+	before := apiKeyResponse(apiKey)
+	if err := database.DB.Model(&apiKey).Update("revoked_at", time.Now()).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke API key"})
+		return
+	}
+
+	audit.Record(c, audit.Entry{
+		Action:     audit.ActionAPIKeyRevoke,
+		TargetType: "api_key",
+		TargetID:   apiKey.ID,
+		Before:     before,
+		After:      apiKeyResponse(apiKey),
+	})
+
+	c.JSON(http.StatusOK, gin.H{"message": "API key revoked successfully"})
+}
+
+func apiKeyResponse(key models.APIKey) gin.H {
+	return gin.H{
+		"id":           key.ID,
+		"name":         key.Name,
+		"prefix":       key.Prefix,
+		"scopes":       key.ScopeList(),
+		"expires_at":   key.ExpiresAt,
+		"last_used_at": key.LastUsedAt,
+		"last_used_ip": key.LastUsedIP,
+		"revoked_at":   key.RevokedAt,
+		"created_at":   key.CreatedAt,
+	}
+}
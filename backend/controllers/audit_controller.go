@@ -0,0 +1,60 @@
+package controllers
+
+import (
+	"backend/database"
+	"backend/models"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetAuditEvents returns a page of audit events, newest first. It can be
+// filtered by actor_id, action, target_type, target_id and a from/to
+// range in RFC 3339.
+func GetAuditEvents(c *gin.Context) {
+	page, limit := parsePagination(c)
+
+	query := database.DB.Model(&models.AuditEvent{})
+	if actorID := c.Query("actor_id"); actorID != "" {
+		query = query.Where("actor_admin_id = ?", actorID)
+	}
+	if action := c.Query("action"); action != "" {
+		query = query.Where("action = ?", action)
+	}
+	if targetType := c.Query("target_type"); targetType != "" {
+		query = query.Where("target_type = ?", targetType)
+	}
+	if targetID := c.Query("target_id"); targetID != "" {
+		query = query.Where("target_id = ?", targetID)
+	}
+	for param, op := range map[string]string{"from": ">=", "to": "<="} {
+		value := c.Query(param)
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + param + " timestamp, expected RFC 3339"})
+			return
+		}
+		query = query.Where("created_at "+op+" ?", t)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch audit events"})
+		return
+	}
+
+	var events []models.AuditEvent
+	if err := query.Order("created_at DESC, id DESC").Offset((page - 1) * limit).Limit(limit).Find(&events).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch audit events"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":       events,
+		"pagination": paginationMeta(page, limit, total),
+	})
+}
@@ -1,9 +1,14 @@
 package controllers
 
 import (
+	"backend/audit"
 	"backend/database"
+	"backend/markup"
 	"backend/models"
+	"backend/ogcard"
+	"backend/utils"
 	"errors"
+	"log"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -11,7 +16,7 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 const (
@@ -20,13 +25,18 @@ const (
 	allowedFormats = ".jpg,.jpeg,.png,.gif"
 )
 
+var (
+	errInvalidSlug = errors.New("invalid slug")
+	errSlugTaken   = errors.New("slug already in use")
+)
+
 func init() {
 	if err := os.MkdirAll(uploadDir, 0755); err != nil {
 		panic("Failed to create upload directory: " + err.Error())
 	}
 }
 
-// CreateBlog creates a new blog post
+// CreateBlog creates a new blog post as a draft
 func CreateBlog(c *gin.Context) {
 	adminID, err := getAdminID(c)
 	if err != nil {
@@ -53,86 +63,303 @@ func CreateBlog(c *gin.Context) {
 		return
 	}
 
+	// New posts are written in Markdown unless the form says otherwise
+	format := c.DefaultPostForm("content_format", markup.FormatMarkdown)
+	if !markup.IsValidFormat(format) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid content format"})
+		return
+	}
+	rendered, err := markup.Render(content, format)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to render content"})
+		return
+	}
+
+	seo, err := readBlogSEO(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	file, err := c.FormFile("image")
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Image is required"})
 		return
 	}
 
-	ext := filepath.Ext(file.Filename)
-	if !isAllowedExtension(ext) {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid file format. Allowed: " + allowedFormats})
+	newFilename, err := saveImage(c, file)
+	if err != nil {
+		respondImageError(c, err)
 		return
 	}
-
-	newFilename := uuid.New().String() + ext
 	filePath := filepath.Join(uploadDir, newFilename)
 
-	if err := c.SaveUploadedFile(file, filePath); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save image"})
-		return
+	// The link preview image is optional and defaults to the post image
+	var ogImage *string
+	if ogFile, err := c.FormFile("og_image"); err == nil {
+		filename, err := saveImage(c, ogFile)
+		if err != nil {
+			os.Remove(filePath)
+			respondImageError(c, err)
+			return
+		}
+		ogImage = &filename
 	}
 
 	// Create blog struct directly with validated data
 	blog := models.Blog{
-		Title:     title,
-		Content:   content,
-		Image:     &newFilename,
-		AdminID:   adminID,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+		Title:         title,
+		Content:       content,
+		ContentFormat: format,
+		ContentHTML:   rendered.HTML,
+		Excerpt:       rendered.Excerpt,
+		WordCount:     rendered.WordCount,
+		ReadingTime:   rendered.ReadingTime,
+		Image:         &newFilename,
+		OGImage:       ogImage,
+		Status:        models.BlogStatusDraft,
+		AdminID:       adminID,
+		CreatedAt:     time.Now(),
+		UpdatedAt:     time.Now(),
 	}
 
-	if err := database.DB.Create(&blog).Error; err != nil {
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		rawSlug, explicit := c.GetPostForm("slug")
+		slug, err := pickBlogSlug(tx, rawSlug, title, explicit, 0)
+		if err != nil {
+			return err
+		}
+		blog.Slug = slug
+		if err := tx.Create(&blog).Error; err != nil {
+			return err
+		}
+		if len(seo) > 0 {
+			if err := tx.Model(&blog).Updates(seo).Error; err != nil {
+				return err
+			}
+		}
+		if err := setBlogTerms(c, tx, &blog); err != nil {
+			return err
+		}
+		_, err = database.RecordBlogRevision(tx, blog, adminID)
+		return err
+	})
+	if err != nil {
 		os.Remove(filePath)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create blog"})
+		if ogImage != nil {
+			os.Remove(filepath.Join(uploadDir, *ogImage))
+		}
+		respondBlogError(c, err, "Failed to create blog")
 		return
 	}
 
+	database.DB.Scopes(withBlogRelations).First(&blog, blog.ID)
+
+	audit.Record(c, audit.Entry{
+		Action:     audit.ActionBlogCreate,
+		TargetType: "blog",
+		TargetID:   blog.ID,
+		After:      blog,
+	})
+
 	c.JSON(http.StatusCreated, gin.H{
 		"message": "Blog created successfully",
-		"blog":    blog,
+		"blog":    models.NewBlogResponse(blog),
 	})
 }
 
-// GetBlogs returns all blogs
+// blogSortColumns lists the columns the blog lists can be sorted by
+var blogSortColumns = map[string]string{
+	"created_at":   "created_at",
+	"updated_at":   "updated_at",
+	"published_at": "published_at",
+	"title":        "title",
+}
+
+// publishedBlogs limits a query to posts readers may see
+func publishedBlogs(db *gorm.DB) *gorm.DB {
+	return db.Where("status = ?", models.BlogStatusPublished)
+}
+
+// anyBlog leaves a query unrestricted, for the admin endpoints
+func anyBlog(db *gorm.DB) *gorm.DB {
+	return db
+}
+
+// withBlogRelations loads what NewBlogResponse shows alongside a blog
+func withBlogRelations(db *gorm.DB) *gorm.DB {
+	return db.Preload("Admin").
+		Preload("Tags", func(db *gorm.DB) *gorm.DB { return db.Order("tags.name") }).
+		Preload("Categories", func(db *gorm.DB) *gorm.DB { return db.Order("categories.name") })
+}
+
+// GetBlogs returns a page of published blogs. It accepts ?page=&limit=,
+// filters ?author=<admin id>, ?tag=<slug>, ?category=<slug> (including
+// subcategories), ?from= and ?to= on the creation date, and ?sort= with
+// an optional "-" prefix for descending order.
 func GetBlogs(c *gin.Context) {
+	listBlogs(c, database.DB.Model(&models.Blog{}).Scopes(publishedBlogs), "-published_at", nil)
+}
+
+// GetAdminBlogs returns a page of blogs in every status for the
+// dashboard. It takes the same parameters as GetBlogs plus ?status=.
+func GetAdminBlogs(c *gin.Context) {
+	query := database.DB.Model(&models.Blog{})
+	if status := c.Query("status"); status != "" {
+		if !models.BlogStatus(status).IsValid() {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status"})
+			return
+		}
+		query = query.Where("status = ?", status)
+	}
+	listBlogs(c, query, "-created_at", nil)
+}
+
+// listBlogs writes a page of the blogs matched by query, applying the
+// common filters. Extra fields are added to the response envelope.
+func listBlogs(c *gin.Context, query *gorm.DB, defaultSort string, extra gin.H) {
+	page, limit := parsePagination(c)
+
+	if author := c.Query("author"); author != "" {
+		authorID, err := strconv.ParseUint(author, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid author"})
+			return
+		}
+		query = query.Where("admin_id = ?", authorID)
+	}
+	if tag := c.Query("tag"); tag != "" {
+		query = query.Where("id IN (?)", taggedBlogIDs(tag))
+	}
+	if category := c.Query("category"); category != "" {
+		ids, err := categoryTreeIDs(category)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch blogs"})
+			return
+		}
+		query = query.Where("id IN (?)", categorizedBlogIDs(ids))
+	}
+	if from := c.Query("from"); from != "" {
+		t, err := parseDateParam(from, false)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid from date"})
+			return
+		}
+		query = query.Where("created_at >= ?", t)
+	}
+	if to := c.Query("to"); to != "" {
+		t, err := parseDateParam(to, true)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid to date"})
+			return
+		}
+		query = query.Where("created_at <= ?", t)
+	}
+
+	order, ok := parseSort(c, blogSortColumns, defaultSort)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid sort field"})
+		return
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch blogs"})
+		return
+	}
+
 	var blogs []models.Blog
-	if err := database.DB.Preload("Admin").Order("created_at DESC").Find(&blogs).Error; err != nil {
+	if err := query.Scopes(withBlogRelations).
+		Order(order).Order("id DESC").
+		Offset((page - 1) * limit).Limit(limit).
+		Find(&blogs).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch blogs"})
 		return
 	}
 
-	for i := range blogs {
-		if blogs[i].Image != nil {
-			imagePath := "/uploads/" + *blogs[i].Image
-			blogs[i].Image = &imagePath
-		}
+	response := gin.H{
+		"data":       models.NewBlogResponses(blogs),
+		"pagination": paginationMeta(page, limit, total),
+	}
+	for key, value := range extra {
+		response[key] = value
 	}
 
-	c.JSON(http.StatusOK, blogs)
+	setLinkHeader(c, page, limit, total)
+	c.JSON(http.StatusOK, response)
 }
 
-// GetBlog returns a single blog by ID
+// GetBlog returns a single published blog by numeric ID or by slug. A
+// slug the blog used before a rename answers with a 301 to the current
+// slug.
 func GetBlog(c *gin.Context) {
-	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+	findBlog(c, publishedBlogs, "/api/admin/blogs/")
+}
+
+// GetAdminBlog returns a single blog in any status, like GetBlog
+func GetAdminBlog(c *gin.Context) {
+	findBlog(c, anyBlog, "/api/admin/manage/blogs/")
+}
+
+func findBlog(c *gin.Context, scope func(*gorm.DB) *gorm.DB, basePath string) {
+	param := c.Param("id")
+
+	var blog models.Blog
+	if id, err := strconv.ParseUint(param, 10, 64); err == nil {
+		if err := database.DB.Scopes(scope, withBlogRelations).First(&blog, id).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Blog not found"})
+			return
+		}
+		c.JSON(http.StatusOK, models.NewBlogResponse(blog))
 		return
 	}
 
-	var blog models.Blog
-	if err := database.DB.Preload("Admin").First(&blog, id).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Blog not found"})
+	err := database.DB.Scopes(scope, withBlogRelations).Where("slug = ?", param).First(&blog).Error
+	if err == nil {
+		c.JSON(http.StatusOK, models.NewBlogResponse(blog))
+		return
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch blog"})
 		return
 	}
 
-	if blog.Image != nil {
-		imagePath := "/uploads/" + *blog.Image
-		blog.Image = &imagePath
+	var history models.BlogSlugHistory
+	if err := database.DB.Where("slug = ?", param).First(&history).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Blog not found"})
+		return
+	}
+	if err := database.DB.Scopes(scope).Select("id", "slug").First(&blog, history.BlogID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Blog not found"})
+		return
 	}
 
-	c.JSON(http.StatusOK, blog)
+	location := basePath + blog.Slug
+	c.Header("Location", location)
+	c.JSON(http.StatusMovedPermanently, gin.H{
+		"slug":        blog.Slug,
+		"redirect_to": location,
+	})
+}
+
+// findPublishedBlog looks up the published blog in the :id parameter by
+// numeric ID or slug. It writes the 404 itself and reports whether the
+// blog was found.
+func findPublishedBlog(c *gin.Context, query *gorm.DB) (models.Blog, bool) {
+	var blog models.Blog
+	query = query.Scopes(publishedBlogs)
+
+	var err error
+	if id, parseErr := strconv.ParseUint(c.Param("id"), 10, 64); parseErr == nil {
+		err = query.First(&blog, id).Error
+	} else {
+		err = query.Where("slug = ?", c.Param("id")).First(&blog).Error
+	}
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Blog not found"})
+		return blog, false
+	}
+	return blog, true
 }
 
 // UpdateBlog updates an existing blog
@@ -155,56 +382,168 @@ func UpdateBlog(c *gin.Context) {
 		return
 	}
 
-	if blog.AdminID != adminID {
+	if !canEditContent(c, blog, adminID) {
 		c.JSON(http.StatusForbidden, gin.H{"error": "Not authorized to update this blog"})
 		return
 	}
 
-	var updateData models.Blog
-	if err := c.ShouldBind(&updateData); err != nil {
+	if err := c.Request.ParseMultipartForm(maxUploadSize); err != nil && !errors.Is(err, http.ErrNotMultipart) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "File too large (max 8MB)"})
+		return
+	}
+
+	updates := map[string]interface{}{"updated_at": time.Now()}
+	if title, ok := c.GetPostForm("title"); ok {
+		if title == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Title is required"})
+			return
+		}
+		updates["title"] = title
+	}
+	if content, ok := c.GetPostForm("content"); ok {
+		if content == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Content is required"})
+			return
+		}
+		updates["content"] = content
+	}
+	if format, ok := c.GetPostForm("content_format"); ok {
+		if !markup.IsValidFormat(format) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid content format"})
+			return
+		}
+		updates["content_format"] = format
+	}
+
+	seo, err := readBlogSEO(c)
+	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	for column, value := range seo {
+		updates[column] = value
+	}
+
+	// Re-derive the rendered fields whenever the source changes
+	_, contentChanged := updates["content"]
+	_, formatChanged := updates["content_format"]
+	if contentChanged || formatChanged {
+		source, format := blog.Content, blog.ContentFormat
+		if value, ok := updates["content"].(string); ok {
+			source = value
+		}
+		if value, ok := updates["content_format"].(string); ok {
+			format = value
+		}
+		rendered, err := markup.Render(source, format)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to render content"})
+			return
+		}
+		for column, value := range rendered.Fields() {
+			updates[column] = value
+		}
+	}
 
+	var newFilePath string
 	file, err := c.FormFile("image")
 	if err == nil {
-		ext := filepath.Ext(file.Filename)
-		if !isAllowedExtension(ext) {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid file format"})
+		newFilename, err := saveImage(c, file)
+		if err != nil {
+			respondImageError(c, err)
 			return
 		}
+		newFilePath = filepath.Join(uploadDir, newFilename)
+		updates["image"] = newFilename
+	}
 
-		newFilename := uuid.New().String() + ext
-		filePath := filepath.Join(uploadDir, newFilename)
-
-		if err := c.SaveUploadedFile(file, filePath); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save image"})
+	// A new preview image replaces the old one; an empty field clears it
+	var newOGPath string
+	if ogFile, err := c.FormFile("og_image"); err == nil {
+		filename, err := saveImage(c, ogFile)
+		if err != nil {
+			if newFilePath != "" {
+				os.Remove(newFilePath)
+			}
+			respondImageError(c, err)
 			return
 		}
+		newOGPath = filepath.Join(uploadDir, filename)
+		updates["og_image"] = filename
+	} else if value, ok := c.GetPostForm("og_image"); ok && value == "" {
+		updates["og_image"] = nil
+	}
+
+	before := blog
+	var prunedImages []string
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := lockBlogStatus(tx, before); err != nil {
+			return err
+		}
+		if err := database.EnsureBlogRevisions(tx, before); err != nil {
+			return err
+		}
 
-		if blog.Image != nil && *blog.Image != "" {
-			oldPath := filepath.Join(uploadDir, *blog.Image)
-			if err := os.Remove(oldPath); err != nil && !os.IsNotExist(err) {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove old image"})
-				return
+		// An explicit slug wins; otherwise a new title brings a new slug
+		rawSlug, explicit := c.GetPostForm("slug")
+		title, renamed := updates["title"].(string)
+		if explicit || (renamed && title != blog.Title) {
+			if !renamed {
+				title = blog.Title
 			}
+			slug, err := pickBlogSlug(tx, rawSlug, title, explicit, blog.ID)
+			if err != nil {
+				return err
+			}
+			if err := database.ChangeBlogSlug(tx, &blog, slug); err != nil {
+				return err
+			}
+		}
+		if err := tx.Model(&blog).Updates(updates).Error; err != nil {
+			return err
 		}
+		if err := setBlogTerms(c, tx, &blog); err != nil {
+			return err
+		}
+		if err := tx.First(&blog, blog.ID).Error; err != nil {
+			return err
+		}
+		prunedImages, err = database.RecordBlogRevision(tx, blog, adminID)
+		return err
+	})
+	if err != nil {
+		if newFilePath != "" {
+			os.Remove(newFilePath)
+		}
+		if newOGPath != "" {
+			os.Remove(newOGPath)
+		}
+		respondBlogError(c, err, "Failed to update blog")
+		return
+	}
 
-		updateData.Image = &newFilename
-	} else {
-		updateData.Image = blog.Image
+	// The replaced image stays on disk while a revision still uses it
+	removeOrphanImages(prunedImages...)
+	if cardChanged(before, blog) {
+		ogcard.Invalidate(blog.ID)
+	}
+	if _, replaced := updates["og_image"]; replaced && before.OGImage != nil {
+		removeOrphanImages(*before.OGImage)
 	}
 
-	updateData.UpdatedAt = time.Now()
+	database.DB.Scopes(withBlogRelations).First(&blog, blog.ID)
 
-	if err := database.DB.Model(&blog).Updates(updateData).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update blog"})
-		return
-	}
+	audit.Record(c, audit.Entry{
+		Action:     audit.ActionBlogUpdate,
+		TargetType: "blog",
+		TargetID:   blog.ID,
+		Before:     before,
+		After:      blog,
+	})
 
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Blog updated successfully",
-		"blog":    blog,
+		"blog":    models.NewBlogResponse(blog),
 	})
 }
 
@@ -228,28 +567,101 @@ func DeleteBlog(c *gin.Context) {
 		return
 	}
 
-	if blog.AdminID != adminID {
+	if !canModify(c, blog, adminID, models.PermBlogDeleteAny) {
 		c.JSON(http.StatusForbidden, gin.H{"error": "Not authorized to delete this blog"})
 		return
 	}
 
-	if err := database.DB.Delete(&blog).Error; err != nil {
+	var images []string
+	if blog.Image != nil {
+		images = append(images, *blog.Image)
+	}
+	if blog.OGImage != nil {
+		images = append(images, *blog.OGImage)
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		var revisionImages []string
+		if err := tx.Model(&models.BlogRevision{}).Where("blog_id = ? AND image IS NOT NULL", blog.ID).
+			Distinct().Pluck("image", &revisionImages).Error; err != nil {
+			return err
+		}
+		images = append(images, revisionImages...)
+
+		if err := tx.Where("blog_id = ?", blog.ID).Delete(&models.BlogRevision{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("blog_id = ?", blog.ID).Delete(&models.BlogSlugHistory{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("blog_id = ?", blog.ID).Delete(&models.Comment{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&blog).Association("Tags").Clear(); err != nil {
+			return err
+		}
+		if err := tx.Model(&blog).Association("Categories").Clear(); err != nil {
+			return err
+		}
+		return tx.Delete(&blog).Error
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete blog"})
 		return
 	}
 
-	if blog.Image != nil && *blog.Image != "" {
-		imagePath := filepath.Join(uploadDir, *blog.Image)
-		if err := os.Remove(imagePath); err != nil && !os.IsNotExist(err) {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"message": "Blog deleted but failed to remove image",
-				"error":   err.Error(),
-			})
-			return
+	audit.Record(c, audit.Entry{
+		Action:     audit.ActionBlogDelete,
+		TargetType: "blog",
+		TargetID:   blog.ID,
+		Before:     blog,
+	})
+
+	removeOrphanImages(images...)
+	ogcard.Invalidate(blog.ID)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Blog deleted successfully"})
+}
+
+// pickBlogSlug settles the slug for a blog. An explicit slug from the
+// admin is normalised and must be free; one derived from the title gets
+// a numeric suffix until it is.
+func pickBlogSlug(tx *gorm.DB, rawSlug, title string, explicit bool, blogID uint) (string, error) {
+	if explicit {
+		slug := utils.Slugify(rawSlug)
+		if slug == "" || utils.IsNumericSlug(slug) {
+			return "", errInvalidSlug
 		}
+		taken, err := database.BlogSlugTaken(tx, slug, blogID)
+		if err != nil {
+			return "", err
+		}
+		if taken {
+			return "", errSlugTaken
+		}
+		return slug, nil
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Blog deleted successfully"})
+	return database.UniqueBlogSlug(tx, utils.Slugify(title), blogID)
+}
+
+// respondBlogError maps slug, taxonomy and workflow failures to client
+// errors and anything else to a 500 with the given message
+func respondBlogError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, errInvalidSlug):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Slug must contain at least one letter"})
+	case errors.Is(err, errSlugTaken):
+		c.JSON(http.StatusConflict, gin.H{"error": "Slug is already used by another blog"})
+	case errors.Is(err, errUnknownCategory):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown category"})
+	case errors.Is(err, errStatusChanged):
+		c.JSON(http.StatusConflict, gin.H{"error": "Blog status was changed by someone else; reload and try again"})
+	case errors.Is(err, errUnknownTag):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown tag"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
 }
 
 func getAdminID(c *gin.Context) (uint, error) {
@@ -266,6 +678,61 @@ func getAdminID(c *gin.Context) (uint, error) {
 	return adminID, nil
 }
 
+func getAdminRole(c *gin.Context) (models.Role, error) {
+	roleValue, exists := c.Get("adminRole")
+	if !exists {
+		return "", errors.New("adminRole not found")
+	}
+
+	role, ok := roleValue.(models.Role)
+	if !ok {
+		return "", errors.New("invalid adminRole type")
+	}
+
+	return role, nil
+}
+
+// canModify reports whether the admin may change the blog: authors can
+// always touch their own posts, anyone else needs the "any" permission
+func canModify(c *gin.Context, blog models.Blog, adminID uint, anyPermission string) bool {
+	if blog.AdminID == adminID {
+		return true
+	}
+	return hasPermission(c, anyPermission)
+}
+
+// hasPermission mirrors middleware.RequirePermission for checks that can
+// only be made inside a handler
+func hasPermission(c *gin.Context, permission string) bool {
+	role, err := getAdminRole(c)
+	if err != nil || !role.Can(permission) {
+		return false
+	}
+	if scopes, isAPIKey := c.Get("apiKeyScopes"); isAPIKey {
+		list, _ := scopes.([]string)
+		return models.ScopesGrant(list, permission)
+	}
+	return true
+}
+
+// removeOrphanImages deletes uploaded images that no blog or revision
+// refers to any more
+func removeOrphanImages(images ...string) {
+	for _, image := range images {
+		if image == "" {
+			continue
+		}
+		inUse, err := database.ImageInUse(database.DB, image)
+		if err != nil || inUse {
+			continue
+		}
+		path := filepath.Join(uploadDir, image)
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			log.Printf("Failed to remove image %s: %v", path, err)
+		}
+	}
+}
+
 func isAllowedExtension(ext string) bool {
 	allowed := map[string]bool{
 		".jpg":  true,
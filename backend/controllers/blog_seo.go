@@ -0,0 +1,96 @@
+package controllers
+
+import (
+	"backend/models"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+var errInvalidImageFormat = errors.New("invalid image format")
+
+// readBlogSEO validates the SEO fields present in the form and returns
+// them as columns to save. Fields left out of the form are left out of
+// the result, so updates only touch what was sent.
+func readBlogSEO(c *gin.Context) (map[string]interface{}, error) {
+	fields := make(map[string]interface{})
+
+	if title, ok := c.GetPostForm("meta_title"); ok {
+		title = strings.TrimSpace(title)
+		if utf8.RuneCountInString(title) > models.MaxMetaTitleLength {
+			return nil, fmt.Errorf("Meta title must be at most %d characters", models.MaxMetaTitleLength)
+		}
+		fields["meta_title"] = title
+	}
+
+	if description, ok := c.GetPostForm("meta_description"); ok {
+		// Head tags are a single line
+		description = strings.Join(strings.Fields(description), " ")
+		if utf8.RuneCountInString(description) > models.MaxMetaDescriptionLength {
+			return nil, fmt.Errorf("Meta description must be at most %d characters", models.MaxMetaDescriptionLength)
+		}
+		fields["meta_description"] = description
+	}
+
+	if canonical, ok := c.GetPostForm("canonical_url"); ok {
+		canonical = strings.TrimSpace(canonical)
+		if canonical != "" && !isAbsoluteHTTPURL(canonical) {
+			return nil, errors.New("Canonical URL must be an absolute http or https URL")
+		}
+		if len(canonical) > models.MaxCanonicalURLLength {
+			return nil, fmt.Errorf("Canonical URL must be at most %d characters", models.MaxCanonicalURLLength)
+		}
+		fields["canonical_url"] = canonical
+	}
+
+	if value, ok := c.GetPostForm("noindex"); ok {
+		noIndex, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, errors.New("noindex must be true or false")
+		}
+		fields["noindex"] = noIndex
+	}
+
+	return fields, nil
+}
+
+func isAbsoluteHTTPURL(value string) bool {
+	u, err := url.Parse(value)
+	if err != nil {
+		return false
+	}
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// saveImage stores an uploaded image under a fresh name and returns the
+// name
+func saveImage(c *gin.Context, file *multipart.FileHeader) (string, error) {
+	ext := filepath.Ext(file.Filename)
+	if !isAllowedExtension(ext) {
+		return "", errInvalidImageFormat
+	}
+
+	filename := uuid.New().String() + ext
+	if err := c.SaveUploadedFile(file, filepath.Join(uploadDir, filename)); err != nil {
+		return "", err
+	}
+	return filename, nil
+}
+
+// respondImageError answers a failed saveImage
+func respondImageError(c *gin.Context, err error) {
+	if errors.Is(err, errInvalidImageFormat) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid file format. Allowed: " + allowedFormats})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save image"})
+}
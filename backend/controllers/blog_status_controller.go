@@ -0,0 +1,281 @@
+package controllers
+
+import (
+	"backend/audit"
+	"backend/database"
+	"backend/mailer"
+	"backend/models"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var errStatusChanged = errors.New("blog status changed")
+
+// UpdateBlogStatus moves a blog through the draft/review/published/
+// archived workflow. Authors can submit their own drafts for review or
+// pull them back; anything that changes what readers see needs
+// blog:publish. Submitting for review emails the editors.
+func UpdateBlogStatus(c *gin.Context) {
+	adminID, err := getAdminID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Admin authentication required"})
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var input models.BlogStatusRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		return
+	}
+	if !input.Status.IsValid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status"})
+		return
+	}
+
+	var blog models.Blog
+	if err := database.DB.First(&blog, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Blog not found"})
+		return
+	}
+
+	if !blog.Status.CanTransitionTo(input.Status) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error": fmt.Sprintf("Cannot move a %s blog to %s", blog.Status, input.Status),
+		})
+		return
+	}
+
+	if blog.Status.IsPublic() || input.Status.IsPublic() {
+		if !hasPermission(c, models.PermBlogPublish) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Not authorized to publish or unpublish blogs"})
+			return
+		}
+	} else if !canModify(c, blog, adminID, models.PermBlogUpdateAny) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Not authorized to update this blog"})
+		return
+	}
+
+	before := blog
+	now := time.Now()
+	updates := map[string]interface{}{
+		"status":     input.Status,
+		"updated_at": now,
+	}
+	// The first publication date sticks when a post is republished
+	if input.Status == models.BlogStatusPublished && blog.PublishedAt == nil {
+		updates["published_at"] = now
+	}
+	// Publishing or archiving by hand supersedes any schedule
+	if input.Status.IsPublic() {
+		updates["publish_at"] = nil
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		// The transition was checked against the status read above, so
+		// it only applies if nothing, the scheduler included, moved the
+		// post in the meantime
+		result := tx.Model(&blog).Where("status = ?", before.Status).Updates(updates)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errStatusChanged
+		}
+		return audit.RecordTx(tx, c, audit.Entry{
+			Action:     audit.ActionBlogStatus,
+			TargetType: "blog",
+			TargetID:   blog.ID,
+			Before:     gin.H{"status": before.Status},
+			After:      gin.H{"status": input.Status, "note": input.Note},
+		})
+	})
+	if errors.Is(err, errStatusChanged) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Blog status was changed by someone else; reload and try again"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update blog status"})
+		return
+	}
+
+	if input.Status == models.BlogStatusReview {
+		notifyEditors(blog, adminID, input.Note)
+	}
+
+	database.DB.Scopes(withBlogRelations).First(&blog, blog.ID)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Blog status updated",
+		"blog":    models.NewBlogResponse(blog),
+	})
+}
+
+// ScheduleBlog sets when a draft or a post in review goes live. The
+// scheduler publishes it once publish_at has passed.
+func ScheduleBlog(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var input models.ScheduleBlogRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		return
+	}
+	if !input.PublishAt.After(time.Now()) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "publish_at must be in the future"})
+		return
+	}
+
+	setBlogSchedule(c, id, &input.PublishAt)
+}
+
+// UnscheduleBlog cancels a scheduled publication
+func UnscheduleBlog(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	setBlogSchedule(c, id, nil)
+}
+
+func setBlogSchedule(c *gin.Context, id uint64, publishAt *time.Time) {
+	var blog models.Blog
+	if err := database.DB.First(&blog, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Blog not found"})
+		return
+	}
+
+	if blog.Status != models.BlogStatusDraft && blog.Status != models.BlogStatusReview {
+		c.JSON(http.StatusConflict, gin.H{"error": "Only drafts and posts in review can be scheduled"})
+		return
+	}
+
+	action := audit.ActionBlogSchedule
+	if publishAt == nil {
+		if blog.PublishAt == nil {
+			c.JSON(http.StatusConflict, gin.H{"error": "Blog is not scheduled"})
+			return
+		}
+		action = audit.ActionBlogUnschedule
+	}
+
+	before := blog.PublishAt
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&blog).Updates(map[string]interface{}{
+			"publish_at": publishAt,
+			"updated_at": time.Now(),
+		}).Error; err != nil {
+			return err
+		}
+		return audit.RecordTx(tx, c, audit.Entry{
+			Action:     action,
+			TargetType: "blog",
+			TargetID:   blog.ID,
+			Before:     gin.H{"publish_at": before},
+			After:      gin.H{"publish_at": publishAt},
+		})
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update blog schedule"})
+		return
+	}
+
+	database.DB.Scopes(withBlogRelations).First(&blog, blog.ID)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Blog schedule updated",
+		"blog":    models.NewBlogResponse(blog),
+	})
+}
+
+// notifyEditors emails every active admin who can publish that blog is
+// waiting for review, except the admin who submitted it. The mail goes
+// out in the background.
+func notifyEditors(blog models.Blog, submitterID uint, note string) {
+	var editors []models.Admin
+	if err := database.DB.
+		Where("role IN ? AND disabled_at IS NULL AND id <> ?", models.RolesWith(models.PermBlogPublish), submitterID).
+		Find(&editors).Error; err != nil {
+		log.Printf("Failed to look up editors for blog %d: %v", blog.ID, err)
+		return
+	}
+
+	var submitter models.Admin
+	database.DB.First(&submitter, submitterID)
+
+	for _, editor := range editors {
+		mailer.SendInBackground(reviewRequestMessage(editor, submitter, blog, note),
+			fmt.Sprintf("review request for blog %d to admin %d", blog.ID, editor.ID))
+	}
+}
+
+// canEditContent reports whether the admin may change what a post says.
+// Once a post is public or scheduled to go live, rewriting it is as good
+// as publishing, so that also takes blog:publish.
+func canEditContent(c *gin.Context, blog models.Blog, adminID uint) bool {
+	if !canModify(c, blog, adminID, models.PermBlogUpdateAny) {
+		return false
+	}
+	if blog.Status.IsPublic() || blog.PublishAt != nil {
+		return hasPermission(c, models.PermBlogPublish)
+	}
+	return true
+}
+
+// lockBlogStatus locks blog's row for the rest of tx and fails with
+// errStatusChanged if the post was moved through the workflow, or
+// scheduled, since it was read and the edit authorised
+func lockBlogStatus(tx *gorm.DB, blog models.Blog) error {
+	var current models.Blog
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id", "status", "publish_at").
+		First(&current, blog.ID).Error; err != nil {
+		return err
+	}
+	if current.Status != blog.Status || (current.PublishAt == nil) != (blog.PublishAt == nil) {
+		return errStatusChanged
+	}
+	return nil
+}
+
+func reviewRequestMessage(editor, submitter models.Admin, blog models.Blog, note string) mailer.Message {
+	link := "/api/admin/manage/blogs/" + strconv.FormatUint(uint64(blog.ID), 10)
+	if base := os.Getenv("REVIEW_URL_BASE"); base != "" {
+		link = strings.TrimRight(base, "/") + "/" + strconv.FormatUint(uint64(blog.ID), 10)
+	}
+
+	body := fmt.Sprintf(
+		"Hello %s,\n\n%s submitted \"%s\" for review.\n\n%s\n",
+		editor.Username, submitter.Username, blog.Title, link,
+	)
+	if note != "" {
+		body += "\nNote from the author:\n" + note + "\n"
+	}
+
+	return mailer.Message{
+		To:      []string{editor.Email},
+		Subject: "Review requested: " + blog.Title,
+		Body:    body,
+	}
+}
@@ -0,0 +1,394 @@
+package controllers
+
+import (
+	"backend/audit"
+	"backend/config"
+	"backend/database"
+	"backend/mailer"
+	"backend/models"
+	"backend/throttle"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// commentLimiter counts every submission from an IP, so a reader can
+// post a few comments in a row before having to wait
+var commentLimiter = &throttle.Limiter{Policy: throttle.Policy{
+	FreeAttempts:     3,
+	BaseDelay:        30 * time.Second,
+	MaxDelay:         10 * time.Minute,
+	LockoutThreshold: 20,
+	LockoutDuration:  time.Hour,
+	Window:           time.Hour,
+}}
+
+var errInvalidParent = errors.New("invalid parent comment")
+
+// GetBlogComments returns the approved comments of a published blog, by
+// numeric ID or slug, as a tree of replies in the order they were posted
+func GetBlogComments(c *gin.Context) {
+	blog, ok := findPublishedBlog(c, database.DB.Select("id"))
+	if !ok {
+		return
+	}
+
+	var comments []models.Comment
+	if err := database.DB.
+		Where("blog_id = ? AND status = ?", blog.ID, models.CommentStatusApproved).
+		Order("created_at ASC").Order("id ASC").
+		Find(&comments).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch comments"})
+		return
+	}
+
+	tree, count := commentTree(comments)
+	c.JSON(http.StatusOK, gin.H{"data": tree, "count": count})
+}
+
+// CreateComment takes a reader's comment, or reply when parent_id is set,
+// into the moderation queue and tells the post's author about it
+func CreateComment(c *gin.Context) {
+	var input models.CommentRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		return
+	}
+
+	// Every submission counts, checked and recorded in one step so a
+	// burst can't slip past the limit
+	key := "comment:ip:" + c.ClientIP()
+	if result, err := commentLimiter.Attempt(c.Request.Context(), key); err != nil {
+		log.Printf("Comment throttle update failed for %s: %v", key, err)
+	} else if result.Blocked {
+		seconds := int(math.Ceil(result.RetryAfter.Seconds()))
+		c.Header("Retry-After", strconv.Itoa(seconds))
+		c.JSON(http.StatusTooManyRequests, gin.H{
+			"error":       fmt.Sprintf("Too many comments. Try again in %d seconds", seconds),
+			"retry_after": seconds,
+		})
+		return
+	}
+
+	accepted := gin.H{
+		"message": "Comment submitted for moderation",
+		"status":  models.CommentStatusPending,
+	}
+
+	// Bots that fill in the hidden field are told it worked and dropped
+	if strings.TrimSpace(input.Website) != "" {
+		c.JSON(http.StatusAccepted, accepted)
+		return
+	}
+
+	name := strings.TrimSpace(input.Name)
+	body := strings.TrimSpace(input.Body)
+	if name == "" || body == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Name and comment are required"})
+		return
+	}
+
+	blog, ok := findPublishedBlog(c, database.DB.Preload("Admin"))
+	if !ok {
+		return
+	}
+
+	comment := models.Comment{
+		BlogID:      blog.ID,
+		ParentID:    input.ParentID,
+		AuthorName:  name,
+		AuthorEmail: strings.ToLower(strings.TrimSpace(input.Email)),
+		Body:        body,
+		Status:      models.CommentStatusPending,
+		IPAddress:   c.ClientIP(),
+		UserAgent:   truncate(c.Request.UserAgent(), 255),
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if comment.ParentID != nil {
+			// Replies may only answer comments readers can see
+			var parent models.Comment
+			if err := tx.Where("blog_id = ? AND status = ?", blog.ID, models.CommentStatusApproved).
+				First(&parent, *comment.ParentID).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return errInvalidParent
+				}
+				return err
+			}
+		}
+		return tx.Create(&comment).Error
+	})
+	if err != nil {
+		if errors.Is(err, errInvalidParent) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "The comment you are replying to was not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to submit comment"})
+		return
+	}
+
+	notifyCommentAuthor(blog, comment)
+
+	c.JSON(http.StatusAccepted, accepted)
+}
+
+// GetComments returns a page of comments for moderators, newest first.
+// ?status= picks the queue (pending by default, or "all") and ?blog=
+// limits it to one post. The number waiting in each queue comes along.
+func GetComments(c *gin.Context) {
+	query := database.DB.Model(&models.Comment{})
+
+	status := c.DefaultQuery("status", string(models.CommentStatusPending))
+	if status != "all" {
+		if !models.CommentStatus(status).IsValid() {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status"})
+			return
+		}
+		query = query.Where("status = ?", status)
+	}
+
+	if blog := c.Query("blog"); blog != "" {
+		blogID, err := strconv.ParseUint(blog, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid blog"})
+			return
+		}
+		query = query.Where("blog_id = ?", blogID)
+	}
+
+	page, limit := parsePagination(c)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch comments"})
+		return
+	}
+
+	var comments []models.Comment
+	if err := query.
+		Preload("Blog", func(db *gorm.DB) *gorm.DB { return db.Select("id", "title", "slug") }).
+		Order("created_at DESC").Order("id DESC").
+		Offset((page - 1) * limit).Limit(limit).
+		Find(&comments).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch comments"})
+		return
+	}
+
+	var rows []struct {
+		Status models.CommentStatus
+		Count  int64
+	}
+	if err := database.DB.Model(&models.Comment{}).
+		Select("status, COUNT(*) AS count").Group("status").
+		Scan(&rows).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch comments"})
+		return
+	}
+	counts := gin.H{
+		string(models.CommentStatusPending):  int64(0),
+		string(models.CommentStatusApproved): int64(0),
+		string(models.CommentStatusSpam):     int64(0),
+	}
+	for _, row := range rows {
+		counts[string(row.Status)] = row.Count
+	}
+
+	data := make([]models.CommentModerationResponse, 0, len(comments))
+	for _, comment := range comments {
+		data = append(data, models.NewCommentModerationResponse(comment))
+	}
+
+	setLinkHeader(c, page, limit, total)
+	c.JSON(http.StatusOK, gin.H{
+		"data":       data,
+		"counts":     counts,
+		"pagination": paginationMeta(page, limit, total),
+	})
+}
+
+// UpdateCommentStatus approves a comment, marks it as spam or sends it
+// back to the queue
+func UpdateCommentStatus(c *gin.Context) {
+	adminID, err := getAdminID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Admin authentication required"})
+		return
+	}
+
+	var input models.CommentStatusRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		return
+	}
+	if !input.Status.IsValid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status"})
+		return
+	}
+
+	comment, ok := findComment(c)
+	if !ok {
+		return
+	}
+
+	before := comment.Status
+	now := time.Now()
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&comment).Updates(map[string]interface{}{
+			"status":       input.Status,
+			"moderated_by": adminID,
+			"moderated_at": now,
+			"updated_at":   now,
+		}).Error; err != nil {
+			return err
+		}
+		return audit.RecordTx(tx, c, audit.Entry{
+			Action:     audit.ActionCommentModerate,
+			TargetType: "comment",
+			TargetID:   comment.ID,
+			Before:     gin.H{"status": before},
+			After:      gin.H{"status": input.Status},
+		})
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update comment"})
+		return
+	}
+
+	database.DB.Preload("Blog", func(db *gorm.DB) *gorm.DB { return db.Select("id", "title", "slug") }).
+		First(&comment, comment.ID)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Comment updated",
+		"comment": models.NewCommentModerationResponse(comment),
+	})
+}
+
+// DeleteComment removes a comment together with every reply under it
+func DeleteComment(c *gin.Context) {
+	comment, ok := findComment(c)
+	if !ok {
+		return
+	}
+
+	var deleted int64
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Exec(`
+			WITH RECURSIVE thread AS (
+				SELECT id FROM comments WHERE id = ?
+				UNION ALL
+				SELECT comments.id FROM comments JOIN thread ON comments.parent_id = thread.id
+			)
+			DELETE FROM comments WHERE id IN (SELECT id FROM thread)`, comment.ID)
+		if result.Error != nil {
+			return result.Error
+		}
+		deleted = result.RowsAffected
+
+		return audit.RecordTx(tx, c, audit.Entry{
+			Action:     audit.ActionCommentDelete,
+			TargetType: "comment",
+			TargetID:   comment.ID,
+			Before:     comment,
+		})
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete comment"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Comment deleted", "deleted": deleted})
+}
+
+func findComment(c *gin.Context) (models.Comment, bool) {
+	var comment models.Comment
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return comment, false
+	}
+	if err := database.DB.First(&comment, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Comment not found"})
+		return comment, false
+	}
+	return comment, true
+}
+
+// commentTree nests comments under the comments they reply to. Replies
+// whose parent is not among comments are left out, so hiding a comment
+// hides its thread. It returns the roots and how many comments made it
+// into the tree.
+func commentTree(comments []models.Comment) ([]models.CommentResponse, int) {
+	replies := make(map[uint][]models.Comment)
+	var roots []models.Comment
+	for _, comment := range comments {
+		if comment.ParentID == nil {
+			roots = append(roots, comment)
+		} else {
+			replies[*comment.ParentID] = append(replies[*comment.ParentID], comment)
+		}
+	}
+
+	count := 0
+	var build func(models.Comment) models.CommentResponse
+	build = func(comment models.Comment) models.CommentResponse {
+		count++
+		response := models.NewCommentResponse(comment)
+		for _, reply := range replies[comment.ID] {
+			response.Replies = append(response.Replies, build(reply))
+		}
+		return response
+	}
+
+	tree := make([]models.CommentResponse, 0, len(roots))
+	for _, root := range roots {
+		tree = append(tree, build(root))
+	}
+	return tree, count
+}
+
+// notifyCommentAuthor emails the post's author, in the background, that
+// a comment is waiting for moderation
+func notifyCommentAuthor(blog models.Blog, comment models.Comment) {
+	author := blog.Admin
+	if author.ID == 0 || author.IsDisabled() || author.Email == "" {
+		return
+	}
+
+	// The moderation panel lives on the admin dashboard unless
+	// COMMENTS_URL_BASE points somewhere else. The link only ever comes
+	// from configuration; without it the email carries none.
+	base := os.Getenv("COMMENTS_URL_BASE")
+	if base == "" && config.SiteURL() != "" {
+		base = config.SiteURL() + "/admin/dashboard"
+	}
+	moderation := "."
+	if base != "" {
+		link := strings.TrimRight(base, "/") + "?status=pending&blog=" + strconv.FormatUint(uint64(blog.ID), 10)
+		moderation = ":\n" + link
+	}
+
+	kind := "commented on"
+	if comment.ParentID != nil {
+		kind = "replied to a comment on"
+	}
+	body := fmt.Sprintf(
+		"Hello %s,\n\n%s %s \"%s\":\n\n%s\n\nThe comment is waiting for moderation%s\n",
+		author.Username, comment.AuthorName, kind, blog.Title, comment.Body, moderation,
+	)
+
+	message := mailer.Message{
+		To:      []string{author.Email},
+		Subject: "New comment on " + blog.Title,
+		Body:    body,
+	}
+	mailer.SendInBackground(message, fmt.Sprintf("comment notification for blog %d to admin %d", blog.ID, author.ID))
+}
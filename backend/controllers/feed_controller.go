@@ -0,0 +1,228 @@
+package controllers
+
+import (
+	"backend/config"
+	"backend/database"
+	"backend/feed"
+	"backend/models"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// feedItemLimit is how many of the latest posts a feed carries
+const feedItemLimit = 20
+
+const defaultSiteName = "Starlink Education and Visa Services"
+
+// feedFormats maps a feed file extension to its writer and content type
+var feedFormats = map[string]struct {
+	write       func(feed.Feed) ([]byte, error)
+	contentType string
+}{
+	".rss":  {feed.RSS, "application/rss+xml; charset=utf-8"},
+	".atom": {feed.Atom, "application/atom+xml; charset=utf-8"},
+	".json": {feed.JSON, "application/feed+json; charset=utf-8"},
+}
+
+// GetBlogFeed serves the latest published posts as /feeds/blog.rss,
+// /feeds/blog.atom or /feeds/blog.json
+func GetBlogFeed(c *gin.Context) {
+	ext := path.Ext(c.Param("file"))
+	if strings.TrimSuffix(c.Param("file"), ext) != "blog" {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Feed not found"})
+		return
+	}
+
+	query := database.DB.Model(&models.Blog{}).Scopes(publishedBlogs)
+	serveFeed(c, ext, query, siteName(), "/blog", time.Time{})
+}
+
+// GetCategoryFeed serves the latest published posts filed under a
+// category or any of its subcategories, as
+// /feeds/category/<slug>.rss, .atom or .json
+func GetCategoryFeed(c *gin.Context) {
+	ext := path.Ext(c.Param("file"))
+	slug := strings.TrimSuffix(c.Param("file"), ext)
+
+	var category models.Category
+	if err := database.DB.Where("slug = ?", slug).First(&category).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Category not found"})
+		return
+	}
+
+	ids, err := categoryTreeIDs(category.Slug)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build feed"})
+		return
+	}
+
+	query := database.DB.Model(&models.Blog{}).Scopes(publishedBlogs).
+		Where("id IN (?)", categorizedBlogIDs(ids))
+	serveFeed(c, ext, query, siteName()+" - "+category.Name, "/blog?category="+category.Slug, category.UpdatedAt)
+}
+
+// serveFeed writes the posts matched by query in the format named by ext.
+// It answers conditional requests with 304 when nothing has changed since
+// the client's copy; since is a lower bound for Last-Modified, for
+// feeds whose metadata can change independently of their posts.
+func serveFeed(c *gin.Context, ext string, query *gorm.DB, title, sitePath string, since time.Time) {
+	format, ok := feedFormats[ext]
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Feed not found"})
+		return
+	}
+	if !requireSiteURL(c) {
+		return
+	}
+
+	var blogs []models.Blog
+	if err := query.Scopes(withBlogRelations).
+		Order("published_at DESC").Order("id DESC").
+		Limit(feedItemLimit).
+		Find(&blogs).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build feed"})
+		return
+	}
+
+	updated := since
+	for _, blog := range blogs {
+		if blog.UpdatedAt.After(updated) {
+			updated = blog.UpdatedAt
+		}
+	}
+	// HTTP dates only carry whole seconds
+	updated = updated.UTC().Truncate(time.Second)
+
+	etag := feedETag(c.Request.URL.Path, updated, blogs)
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", "public, max-age=300")
+	if !updated.IsZero() {
+		c.Header("Last-Modified", updated.Format(http.TimeFormat))
+	}
+	if feedNotModified(c.Request, etag, updated) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	site := config.SiteURL()
+	f := feed.Feed{
+		Title:       title,
+		Description: "Latest articles from " + title,
+		Link:        site + sitePath,
+		FeedURL:     config.PublicURL() + c.Request.URL.Path,
+		Updated:     updated,
+		Items:       make([]feed.Item, 0, len(blogs)),
+	}
+	for _, blog := range blogs {
+		f.Items = append(f.Items, feedItem(site, blog))
+	}
+
+	body, err := format.write(f)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build feed"})
+		return
+	}
+	c.Data(http.StatusOK, format.contentType, body)
+}
+
+func feedItem(site string, blog models.Blog) feed.Item {
+	link := site + "/blog/" + blog.Slug
+	published := blog.CreatedAt
+	if blog.PublishedAt != nil {
+		published = *blog.PublishedAt
+	}
+
+	item := feed.Item{
+		ID:          link,
+		Title:       blog.Title,
+		Link:        link,
+		Summary:     blog.Excerpt,
+		ContentHTML: blog.ContentHTML,
+		Published:   published,
+		Updated:     blog.UpdatedAt,
+	}
+	if author := models.NewAuthorProfile(blog.Admin); author != nil {
+		item.Author = author.DisplayName
+	}
+	for _, category := range blog.Categories {
+		item.Categories = append(item.Categories, category.Name)
+	}
+	for _, tag := range blog.Tags {
+		item.Categories = append(item.Categories, tag.Name)
+	}
+
+	if blog.Image != nil && *blog.Image != "" {
+		image := &feed.Image{
+			URL:  config.PublicURL() + "/uploads/" + *blog.Image,
+			Type: mime.TypeByExtension(filepath.Ext(*blog.Image)),
+		}
+		if info, err := os.Stat(filepath.Join(uploadDir, *blog.Image)); err == nil {
+			image.Length = info.Size()
+		}
+		if image.Type == "" {
+			image.Type = "application/octet-stream"
+		}
+		item.Image = image
+	}
+	return item
+}
+
+// feedETag fingerprints the feed's path and the state of its posts, so
+// publishing, unpublishing or editing any of them changes it
+func feedETag(path string, updated time.Time, blogs []models.Blog) string {
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s|%d", path, updated.UnixNano())
+	for _, blog := range blogs {
+		fmt.Fprintf(hash, "|%d:%d", blog.ID, blog.UpdatedAt.UnixNano())
+	}
+	return `W/"` + hex.EncodeToString(hash.Sum(nil))[:32] + `"`
+}
+
+// feedNotModified reports whether the client's cached copy is current.
+// If-None-Match takes precedence over If-Modified-Since.
+func feedNotModified(r *http.Request, etag string, updated time.Time) bool {
+	if match := r.Header.Get("If-None-Match"); match != "" {
+		for _, candidate := range strings.Split(match, ",") {
+			candidate = strings.TrimSpace(candidate)
+			if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+				return true
+			}
+		}
+		return false
+	}
+
+	if since := r.Header.Get("If-Modified-Since"); since != "" && !updated.IsZero() {
+		if t, err := http.ParseTime(since); err == nil {
+			return !updated.After(t)
+		}
+	}
+	return false
+}
+
+// requireSiteURL responds 503 and returns false when SITE_URL is unset,
+// as the absolute links in feeds and sitemaps can't be built without it
+func requireSiteURL(c *gin.Context) bool {
+	if config.SiteURL() == "" {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "SITE_URL is not configured"})
+		return false
+	}
+	return true
+}
+
+func siteName() string {
+	if name := os.Getenv("SITE_NAME"); name != "" {
+		return name
+	}
+	return defaultSiteName
+}
@@ -0,0 +1,144 @@
+package controllers
+
+import (
+	"backend/audit"
+	"backend/database"
+	"backend/models"
+	"backend/utils"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const inviteTTL = 72 * time.Hour
+
+// CreateInvite issues a single-use invite token for a new admin. The
+// plain token is only returned in this response.
+func CreateInvite(c *gin.Context) {
+	adminID, err := getAdminID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Admin authentication required"})
+		return
+	}
+
+	var input models.CreateInviteRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		return
+	}
+
+	if !input.Role.IsValid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid role"})
+		return
+	}
+
+	email := strings.ToLower(strings.TrimSpace(input.Email))
+
+	var existing int64
+	if err := database.DB.Model(&models.Admin{}).Where("LOWER(email) = ?", email).Count(&existing).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create invite"})
+		return
+	}
+	if existing > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Email already registered"})
+		return
+	}
+
+	token, err := utils.GenerateOpaqueToken()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not generate token"})
+		return
+	}
+
+	now := time.Now()
+	invite := models.AdminInvite{
+		Email:       email,
+		Role:        input.Role,
+		TokenHash:   utils.HashToken(token),
+		InvitedByID: adminID,
+		ExpiresAt:   now.Add(inviteTTL),
+	}
+
+	// A new invite supersedes any pending one for the same address
+	if err := database.DB.Model(&models.AdminInvite{}).
+		Where("LOWER(email) = ? AND accepted_at IS NULL AND revoked_at IS NULL", email).
+		Update("revoked_at", now).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create invite"})
+		return
+	}
+
+	if err := database.DB.Create(&invite).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create invite"})
+		return
+	}
+	audit.Record(c, audit.Entry{
+		Action:     audit.ActionInviteCreate,
+		TargetType: "invite",
+		TargetID:   invite.ID,
+		After:      invite,
+	})
+
+	response := gin.H{
+		"message": "Invite created successfully",
+		"invite":  invite,
+		"token":   token,
+	}
+	if base := os.Getenv("INVITE_URL_BASE"); base != "" {
+		response["invite_url"] = strings.TrimRight(base, "/") + "?token=" + token
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// GetInvites lists invites that have not been accepted or revoked
+func GetInvites(c *gin.Context) {
+	var invites []models.AdminInvite
+	if err := database.DB.
+		Where("accepted_at IS NULL AND revoked_at IS NULL AND expires_at > ?", time.Now()).
+		Order("created_at DESC").
+		Find(&invites).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch invites"})
+		return
+	}
+
+	c.JSON(http.StatusOK, invites)
+}
+
+// RevokeInvite cancels a pending invite
+func RevokeInvite(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var invite models.AdminInvite
+	if err := database.DB.First(&invite, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Invite not found"})
+		return
+	}
+
+	if !invite.IsPending(time.Now()) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invite is no longer pending"})
+		return
+	}
+
+	before := invite
+	if err := database.DB.Model(&invite).Update("revoked_at", time.Now()).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke invite"})
+		return
+	}
+	audit.Record(c, audit.Entry{
+		Action:     audit.ActionInviteRevoke,
+		TargetType: "invite",
+		TargetID:   invite.ID,
+		Before:     before,
+		After:      invite,
+	})
+
+	c.JSON(http.StatusOK, gin.H{"message": "Invite revoked successfully"})
+}
@@ -0,0 +1,144 @@
+package controllers
+
+import (
+	"backend/audit"
+	"backend/database"
+	"backend/models"
+	"backend/throttle"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Failures are counted per client IP and per account. The IP policy is
+// looser because offices and campuses share addresses.
+var (
+	loginIPLimiter = &throttle.Limiter{Policy: throttle.Policy{
+		FreeAttempts:     10,
+		BaseDelay:        time.Second,
+		MaxDelay:         5 * time.Minute,
+		LockoutThreshold: 50,
+		LockoutDuration:  time.Hour,
+		Window:           time.Hour,
+	}}
+	loginAccountLimiter = &throttle.Limiter{Policy: throttle.Policy{
+		FreeAttempts:     3,
+		BaseDelay:        2 * time.Second,
+		MaxDelay:         5 * time.Minute,
+		LockoutThreshold: 10,
+		LockoutDuration:  15 * time.Minute,
+		Window:           time.Hour,
+	}}
+)
+
+type loginKey struct {
+	limiter *throttle.Limiter
+	key     string
+	// result is set once beginLoginAttempt has counted the attempt
+	result *throttle.Result
+}
+
+// loginKeys returns the throttling keys for an attempt on account
+func loginKeys(c *gin.Context, account string) []loginKey {
+	return []loginKey{
+		{limiter: loginIPLimiter, key: "ip:" + c.ClientIP()},
+		{limiter: loginAccountLimiter, key: account},
+	}
+}
+
+func emailKey(email string) string {
+	return "email:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+// beginLoginAttempt counts the attempt against every key before the
+// credentials are checked, so a burst of concurrent guesses can't all
+// slip past the throttle. It responds with 429 and returns false while
+// any key is blocked, taking back what it already counted.
+func beginLoginAttempt(c *gin.Context, keys []loginKey) bool {
+	for i := range keys {
+		k := &keys[i]
+		result, err := k.limiter.Attempt(c.Request.Context(), k.key)
+		if err != nil {
+			log.Printf("Login throttle check failed for %s: %v", k.key, err)
+			continue
+		}
+		if result.Blocked {
+			forgiveLoginAttempt(c, keys[:i])
+			respondTooManyAttempts(c, result.RetryAfter)
+			return false
+		}
+		k.result = &result
+	}
+	return true
+}
+
+// recordLoginFailure logs a lockout record for keys whose counted
+// attempt crossed the threshold
+func recordLoginFailure(c *gin.Context, keys []loginKey, email string) {
+	for _, k := range keys {
+		if k.result == nil || !k.result.LockedOut {
+			continue
+		}
+
+		lockout := models.LoginLockout{
+			Key:         k.key,
+			Email:       strings.ToLower(strings.TrimSpace(email)),
+			IPAddress:   c.ClientIP(),
+			UserAgent:   truncate(c.Request.UserAgent(), 255),
+			Failures:    k.result.Failures,
+			LockedUntil: time.Now().Add(k.result.RetryAfter),
+		}
+		log.Printf("Login lockout: %s after %d failures until %s", k.key, k.result.Failures, lockout.LockedUntil.Format(time.RFC3339))
+		if err := database.DB.Create(&lockout).Error; err != nil {
+			log.Printf("Failed to record login lockout for %s: %v", k.key, err)
+		}
+		audit.Record(c, audit.Entry{
+			Action:     audit.ActionLoginLockout,
+			TargetType: "login",
+			TargetID:   k.key,
+			After:      lockout,
+		})
+	}
+}
+
+// resetLoginThrottle clears the account key after a successful login.
+// The IP key only has this attempt taken back so one valid account can't
+// launder an attacker's failures.
+func resetLoginThrottle(c *gin.Context, keys []loginKey) {
+	for _, k := range keys {
+		if k.limiter != loginAccountLimiter {
+			forgiveLoginAttempt(c, []loginKey{k})
+			continue
+		}
+		if err := k.limiter.Reset(c.Request.Context(), k.key); err != nil {
+			log.Printf("Login throttle reset failed for %s: %v", k.key, err)
+		}
+	}
+}
+
+// forgiveLoginAttempt takes back the attempt counted against keys
+func forgiveLoginAttempt(c *gin.Context, keys []loginKey) {
+	for _, k := range keys {
+		if k.result == nil {
+			continue
+		}
+		if err := k.limiter.Forgive(c.Request.Context(), k.key); err != nil {
+			log.Printf("Login throttle update failed for %s: %v", k.key, err)
+		}
+	}
+}
+
+func respondTooManyAttempts(c *gin.Context, wait time.Duration) {
+	seconds := int(math.Ceil(wait.Seconds()))
+	c.Header("Retry-After", strconv.Itoa(seconds))
+	c.JSON(http.StatusTooManyRequests, gin.H{
+		"error":       fmt.Sprintf("Too many attempts. Try again in %d seconds", seconds),
+		"retry_after": seconds,
+	})
+}
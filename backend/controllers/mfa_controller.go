@@ -0,0 +1,346 @@
+package controllers
+
+import (
+	"backend/audit"
+	"backend/database"
+	"backend/models"
+	"backend/utils"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const recoveryCodeCount = 10
+
+var errInvalidMFACode = errors.New("invalid two-factor code")
+
+// AdminLoginMFA is the second login step: it exchanges the challenge
+// token from AdminLogin plus a TOTP or recovery code for a session
+func AdminLoginMFA(c *gin.Context) {
+	var input models.MFALoginRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		return
+	}
+	if input.Code == "" && input.RecoveryCode == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "code or recovery_code is required"})
+		return
+	}
+
+	adminID, err := utils.ValidateMFAToken(input.MFAToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired MFA token"})
+		return
+	}
+
+	// Codes are throttled per account so the 6-digit space can't be
+	// walked with a fresh challenge token
+	keys := loginKeys(c, fmt.Sprintf("mfa:%d", adminID))
+	if !beginLoginAttempt(c, keys) {
+		return
+	}
+
+	var admin models.Admin
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&admin, adminID).Error; err != nil {
+			return err
+		}
+		if !admin.MFAEnabled() {
+			return errInvalidMFACode
+		}
+		if input.RecoveryCode != "" {
+			return useRecoveryCode(tx, admin.ID, input.RecoveryCode)
+		}
+		return verifyTOTP(tx, &admin, input.Code)
+	})
+	if errors.Is(err, errInvalidMFACode) {
+		recordLoginFailure(c, keys, admin.Email)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid two-factor code"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired MFA token"})
+		return
+	}
+	resetLoginThrottle(c, keys)
+
+	if admin.IsDisabled() {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Account is disabled"})
+		return
+	}
+
+	completeLogin(c, admin)
+}
+
+// SetupTOTP generates a new secret for the signed-in admin. It only
+// takes effect once ConfirmTOTP proves the authenticator was set up.
+func SetupTOTP(c *gin.Context) {
+	admin, ok := currentAdmin(c)
+	if !ok {
+		return
+	}
+
+	if admin.MFAEnabled() {
+		c.JSON(http.StatusConflict, gin.H{"error": "Two-factor authentication is already enabled"})
+		return
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not generate secret"})
+		return
+	}
+	encrypted, err := utils.EncryptSecret(secret)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not store secret"})
+		return
+	}
+
+	if err := database.DB.Model(&admin).Updates(map[string]interface{}{
+		"totp_secret":    encrypted,
+		"totp_last_step": 0,
+	}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not store secret"})
+		return
+	}
+
+	issuer := os.Getenv("TOTP_ISSUER")
+	if issuer == "" {
+		issuer = "Starlink Admin"
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"secret":           secret,
+		"provisioning_uri": utils.TOTPProvisioningURI(secret, admin.Email, issuer),
+	})
+}
+
+// ConfirmTOTP enables two-factor authentication after checking a code
+// from the newly configured authenticator, and returns recovery codes
+func ConfirmTOTP(c *gin.Context) {
+	admin, ok := currentAdmin(c)
+	if !ok {
+		return
+	}
+
+	var input models.TOTPCodeRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		return
+	}
+
+	if admin.MFAEnabled() {
+		c.JSON(http.StatusConflict, gin.H{"error": "Two-factor authentication is already enabled"})
+		return
+	}
+	if admin.TOTPSecret == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Start two-factor setup first"})
+		return
+	}
+
+	var codes []string
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := verifyTOTP(tx, &admin, input.Code); err != nil {
+			return err
+		}
+		if err := tx.Model(&admin).Update("totp_enabled_at", time.Now()).Error; err != nil {
+			return err
+		}
+		if err := audit.RecordTx(tx, c, audit.Entry{
+			Action:     audit.ActionMFAEnable,
+			TargetType: "admin",
+			TargetID:   admin.ID,
+		}); err != nil {
+			return err
+		}
+		var err error
+		codes, err = replaceRecoveryCodes(tx, admin.ID)
+		return err
+	})
+	if errors.Is(err, errInvalidMFACode) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid two-factor code"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to enable two-factor authentication"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":        "Two-factor authentication enabled",
+		"recovery_codes": codes,
+	})
+}
+
+// DisableTOTP turns two-factor authentication off after re-checking the
+// password and a current code
+func DisableTOTP(c *gin.Context) {
+	admin, ok := currentAdmin(c)
+	if !ok {
+		return
+	}
+
+	var input models.DisableTOTPRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		return
+	}
+
+	if !admin.MFAEnabled() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Two-factor authentication is not enabled"})
+		return
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(admin.Password), []byte(input.Password)); err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Password is incorrect"})
+		return
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := verifyTOTP(tx, &admin, input.Code); err != nil {
+			return err
+		}
+		if err := tx.Model(&admin).Updates(map[string]interface{}{
+			"totp_secret":     "",
+			"totp_enabled_at": nil,
+			"totp_last_step":  0,
+		}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("admin_id = ?", admin.ID).Delete(&models.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		return audit.RecordTx(tx, c, audit.Entry{
+			Action:     audit.ActionMFADisable,
+			TargetType: "admin",
+			TargetID:   admin.ID,
+		})
+	})
+	if errors.Is(err, errInvalidMFACode) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid two-factor code"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to disable two-factor authentication"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Two-factor authentication disabled"})
+}
+
+// RegenerateRecoveryCodes replaces all recovery codes of the signed-in admin
+func RegenerateRecoveryCodes(c *gin.Context) {
+	admin, ok := currentAdmin(c)
+	if !ok {
+		return
+	}
+
+	var input models.TOTPCodeRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		return
+	}
+
+	if !admin.MFAEnabled() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Two-factor authentication is not enabled"})
+		return
+	}
+
+	var codes []string
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := verifyTOTP(tx, &admin, input.Code); err != nil {
+			return err
+		}
+		var err error
+		codes, err = replaceRecoveryCodes(tx, admin.ID)
+		return err
+	})
+	if errors.Is(err, errInvalidMFACode) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid two-factor code"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to regenerate recovery codes"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"recovery_codes": codes})
+}
+
+// verifyTOTP checks a code against the admin's secret and records the
+// time step so the same code cannot be used twice
+func verifyTOTP(tx *gorm.DB, admin *models.Admin, code string) error {
+	secret, err := utils.DecryptSecret(admin.TOTPSecret)
+	if err != nil {
+		return err
+	}
+
+	step, ok := utils.ValidateTOTP(secret, code, time.Now())
+	if !ok || step <= admin.TOTPLastStep {
+		return errInvalidMFACode
+	}
+
+	admin.TOTPLastStep = step
+	return tx.Model(admin).Update("totp_last_step", step).Error
+}
+
+func useRecoveryCode(tx *gorm.DB, adminID uint, code string) error {
+	hash := utils.HashToken(utils.NormalizeRecoveryCode(code))
+	result := tx.Model(&models.RecoveryCode{}).
+		Where("admin_id = ? AND code_hash = ? AND used_at IS NULL", adminID, hash).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errInvalidMFACode
+	}
+	return nil
+}
+
+func replaceRecoveryCodes(tx *gorm.DB, adminID uint) ([]string, error) {
+	if err := tx.Where("admin_id = ?", adminID).Delete(&models.RecoveryCode{}).Error; err != nil {
+		return nil, err
+	}
+
+	codes := make([]string, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		code, err := utils.GenerateRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		if err := tx.Create(&models.RecoveryCode{
+			AdminID:  adminID,
+			CodeHash: utils.HashToken(utils.NormalizeRecoveryCode(code)),
+		}).Error; err != nil {
+			return nil, err
+		}
+		codes = append(codes, code)
+	}
+	return codes, nil
+}
+
+// currentAdmin loads the signed-in admin, responding with an error if
+// that isn't possible
+func currentAdmin(c *gin.Context) (models.Admin, bool) {
+	var admin models.Admin
+
+	adminID, err := getAdminID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Admin authentication required"})
+		return admin, false
+	}
+
+	if err := database.DB.First(&admin, adminID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Admin not found"})
+		return admin, false
+	}
+
+	return admin, true
+}
@@ -0,0 +1,75 @@
+package controllers
+
+import (
+	"backend/database"
+	"backend/models"
+	"backend/ogcard"
+	"log"
+	"net/http"
+	"path/filepath"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetBlogCard serves the Open Graph preview card of a published blog,
+// by numeric ID or slug, as a 1200x630 PNG. Cards are drawn on first
+// request and kept on disk until the title or image changes.
+func GetBlogCard(c *gin.Context) {
+	blog, ok := findPublishedBlog(c, database.DB.Select("id", "title", "image"))
+	if !ok {
+		return
+	}
+
+	imageName := ""
+	if blog.Image != nil {
+		imageName = *blog.Image
+	}
+	key := ogcard.Key(blog.Title, imageName)
+
+	etag := `"` + key + `"`
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", "public, max-age=86400")
+	if feedNotModified(c.Request, etag, time.Time{}) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	path, err := ogcard.Cached(blog.ID, key, func() (ogcard.Card, error) {
+		return blogCard(blog.Title, imageName), nil
+	})
+	if err != nil {
+		log.Printf("Failed to render card for blog %d: %v", blog.ID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to render preview image"})
+		return
+	}
+	c.File(path)
+}
+
+// blogCard gathers what a blog's card shows. A missing or unreadable
+// image or logo leaves that part out rather than failing the card.
+func blogCard(title, imageName string) ogcard.Card {
+	card := ogcard.Card{Title: title}
+
+	if imageName != "" {
+		img, err := ogcard.LoadImage(filepath.Join(uploadDir, imageName))
+		if err != nil {
+			log.Printf("Failed to load image %s for card: %v", imageName, err)
+		} else {
+			card.Image = img
+		}
+	}
+
+	logo, err := ogcard.LoadLogo()
+	if err != nil {
+		log.Printf("Failed to load card logo: %v", err)
+	} else {
+		card.Logo = logo
+	}
+	return card
+}
+
+// cardChanged reports whether an edit touches what the blog's card shows
+func cardChanged(before, after models.Blog) bool {
+	return before.Title != after.Title || !sameImage(before.Image, after.Image)
+}
@@ -0,0 +1,107 @@
+package controllers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// parsePagination reads ?page= and ?limit= with sane defaults and bounds
+func parsePagination(c *gin.Context) (page, limit int) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit, err = strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultPageSize)))
+	if err != nil || limit < 1 {
+		limit = defaultPageSize
+	}
+	if limit > maxPageSize {
+		limit = maxPageSize
+	}
+
+	return page, limit
+}
+
+// paginationMeta builds the metadata block returned with paged lists
+func paginationMeta(page, limit int, total int64) gin.H {
+	totalPages := int((total + int64(limit) - 1) / int64(limit))
+	return gin.H{
+		"page":        page,
+		"limit":       limit,
+		"total":       total,
+		"total_pages": totalPages,
+	}
+}
+
+// setLinkHeader adds an RFC 8288 Link header with first/prev/next/last
+// page URLs, keeping every other query parameter of the request
+func setLinkHeader(c *gin.Context, page, limit int, total int64) {
+	totalPages := int((total + int64(limit) - 1) / int64(limit))
+	if totalPages < 1 {
+		totalPages = 1
+	}
+
+	pageURL := func(p int) string {
+		u := *c.Request.URL
+		q := u.Query()
+		q.Set("page", strconv.Itoa(p))
+		q.Set("limit", strconv.Itoa(limit))
+		u.RawQuery = q.Encode()
+		return u.RequestURI()
+	}
+
+	links := []string{fmt.Sprintf(`<%s>; rel="first"`, pageURL(1))}
+	if page > 1 {
+		links = append(links, fmt.Sprintf(`<%s>; rel="prev"`, pageURL(page-1)))
+	}
+	if page < totalPages {
+		links = append(links, fmt.Sprintf(`<%s>; rel="next"`, pageURL(page+1)))
+	}
+	links = append(links, fmt.Sprintf(`<%s>; rel="last"`, pageURL(totalPages)))
+
+	c.Header("Link", strings.Join(links, ", "))
+	c.Header("X-Total-Count", strconv.FormatInt(total, 10))
+}
+
+// parseSort turns ?sort=field or ?sort=-field into an ORDER BY clause,
+// accepting only the columns in allowed
+func parseSort(c *gin.Context, allowed map[string]string, fallback string) (string, bool) {
+	value := c.DefaultQuery("sort", fallback)
+	direction := "ASC"
+	if strings.HasPrefix(value, "-") {
+		direction = "DESC"
+		value = value[1:]
+	}
+
+	column, ok := allowed[value]
+	if !ok {
+		return "", false
+	}
+	return column + " " + direction, true
+}
+
+// parseDateParam accepts either RFC 3339 or a plain YYYY-MM-DD date. A
+// plain date used as an upper bound covers the whole day.
+func parseDateParam(value string, endOfDay bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if endOfDay {
+		t = t.Add(24*time.Hour - time.Nanosecond)
+	}
+	return t, nil
+}
@@ -0,0 +1,254 @@
+package controllers
+
+import (
+	"backend/audit"
+	"backend/database"
+	"backend/mailer"
+	"backend/models"
+	"backend/throttle"
+	"backend/utils"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const passwordResetTTL = time.Hour
+
+var errInvalidResetToken = errors.New("invalid or expired reset token")
+
+// Reset requests are counted per client IP and per address whether or not
+// the address is registered, so the endpoint can't flood an inbox or be
+// used to probe at volume
+var (
+	passwordResetIPLimiter = &throttle.Limiter{Policy: throttle.Policy{
+		FreeAttempts:     10,
+		BaseDelay:        time.Second,
+		MaxDelay:         10 * time.Minute,
+		LockoutThreshold: 50,
+		LockoutDuration:  time.Hour,
+		Window:           time.Hour,
+	}}
+	passwordResetEmailLimiter = &throttle.Limiter{Policy: throttle.Policy{
+		FreeAttempts:     3,
+		BaseDelay:        time.Minute,
+		MaxDelay:         time.Hour,
+		LockoutThreshold: 10,
+		LockoutDuration:  time.Hour,
+		Window:           time.Hour,
+	}}
+)
+
+// ForgotPassword emails a reset link. The response is the same whether
+// or not the address belongs to an admin, so it can't be used to probe
+// for accounts.
+func ForgotPassword(c *gin.Context) {
+	var input models.ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		return
+	}
+
+	response := gin.H{"message": "If that email is registered, a reset link has been sent"}
+	email := strings.ToLower(strings.TrimSpace(input.Email))
+
+	keys := []loginKey{
+		{limiter: passwordResetIPLimiter, key: "reset:ip:" + c.ClientIP()},
+		{limiter: passwordResetEmailLimiter, key: "reset:" + emailKey(email)},
+	}
+	if !beginLoginAttempt(c, keys) {
+		return
+	}
+
+	var admin models.Admin
+	if err := database.DB.Where("LOWER(email) = ?", email).First(&admin).Error; err != nil || admin.IsDisabled() {
+		c.JSON(http.StatusOK, response)
+		return
+	}
+
+	token, err := utils.GenerateOpaqueToken()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not generate token"})
+		return
+	}
+
+	now := time.Now()
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		// Only the most recent link stays valid
+		if err := tx.Model(&models.PasswordResetToken{}).
+			Where("admin_id = ? AND used_at IS NULL", admin.ID).
+			Update("used_at", now).Error; err != nil {
+			return err
+		}
+		return tx.Create(&models.PasswordResetToken{
+			AdminID:   admin.ID,
+			TokenHash: utils.HashToken(token),
+			ExpiresAt: now.Add(passwordResetTTL),
+		}).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start password reset"})
+		return
+	}
+
+	if err := mailer.Send(passwordResetMessage(admin, token)); err != nil {
+		log.Printf("Failed to send password reset email to admin %d: %v", admin.ID, err)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ResetPassword sets a new password using an emailed token and signs
+// the admin out of every session
+func ResetPassword(c *gin.Context) {
+	var input models.ResetPasswordRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		return
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(input.Password), bcrypt.DefaultCost)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not hash password"})
+		return
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		var reset models.PasswordResetToken
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("token_hash = ?", utils.HashToken(input.Token)).
+			First(&reset).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errInvalidResetToken
+			}
+			return err
+		}
+
+		now := time.Now()
+		if reset.UsedAt != nil || !now.Before(reset.ExpiresAt) {
+			return errInvalidResetToken
+		}
+
+		if err := tx.Model(&reset).Update("used_at", now).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Admin{}).Where("id = ?", reset.AdminID).
+			Update("password", string(hashedPassword)).Error; err != nil {
+			return err
+		}
+		if err := audit.RecordTx(tx, c, audit.Entry{
+			Action:     audit.ActionPasswordReset,
+			TargetType: "admin",
+			TargetID:   reset.AdminID,
+			ActorID:    &reset.AdminID,
+		}); err != nil {
+			return err
+		}
+		return revokeSessions(tx, reset.AdminID)
+	})
+	if errors.Is(err, errInvalidResetToken) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or expired reset token"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reset password"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Password reset successfully"})
+}
+
+// ChangePassword lets the signed-in admin change their password after
+// re-verifying the current one. Other sessions are revoked.
+func ChangePassword(c *gin.Context) {
+	adminID, err := getAdminID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Admin authentication required"})
+		return
+	}
+	sessionID, err := getSessionID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Admin authentication required"})
+		return
+	}
+
+	var input models.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		return
+	}
+
+	// Guesses at the current password are throttled per account, so a
+	// stolen session can't be used to recover it
+	keys := loginKeys(c, fmt.Sprintf("password:%d", adminID))
+	if !beginLoginAttempt(c, keys) {
+		return
+	}
+
+	var admin models.Admin
+	if err := database.DB.First(&admin, adminID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Admin not found"})
+		return
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(admin.Password), []byte(input.CurrentPassword)); err != nil {
+		recordLoginFailure(c, keys, admin.Email)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Current password is incorrect"})
+		return
+	}
+	resetLoginThrottle(c, keys)
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(input.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not hash password"})
+		return
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&admin).Update("password", string(hashedPassword)).Error; err != nil {
+			return err
+		}
+		if err := audit.RecordTx(tx, c, audit.Entry{
+			Action:     audit.ActionPasswordChange,
+			TargetType: "admin",
+			TargetID:   admin.ID,
+		}); err != nil {
+			return err
+		}
+		return tx.Model(&models.AdminSession{}).
+			Where("admin_id = ? AND id <> ? AND revoked_at IS NULL", admin.ID, sessionID).
+			Update("revoked_at", time.Now()).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to change password"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Password changed successfully"})
+}
+
+func passwordResetMessage(admin models.Admin, token string) mailer.Message {
+	link := token
+	if base := os.Getenv("PASSWORD_RESET_URL_BASE"); base != "" {
+		link = strings.TrimRight(base, "/") + "?token=" + token
+	}
+
+	return mailer.Message{
+		To:      []string{admin.Email},
+		Subject: "Reset your Starlink admin password",
+		Body: fmt.Sprintf(
+			"Hello %s,\n\nSomeone asked to reset the password for your admin account.\n"+
+				"Use the link below within %d minutes to choose a new one:\n\n%s\n\n"+
+				"If you didn't ask for this, you can ignore this email.\n",
+			admin.Username, int(passwordResetTTL.Minutes()), link,
+		),
+	}
+}
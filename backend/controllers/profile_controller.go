@@ -0,0 +1,67 @@
+package controllers
+
+import (
+	"backend/audit"
+	"backend/database"
+	"backend/models"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetProfile returns the signed-in admin's public author profile
+func GetProfile(c *gin.Context) {
+	admin, ok := currentAdmin(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewAuthorProfile(admin))
+}
+
+// UpdateProfile changes the display name, avatar and bio shown on the
+// signed-in admin's posts
+func UpdateProfile(c *gin.Context) {
+	admin, ok := currentAdmin(c)
+	if !ok {
+		return
+	}
+
+	var input models.UpdateProfileRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		return
+	}
+
+	updates := map[string]interface{}{}
+	if input.DisplayName != nil {
+		updates["display_name"] = strings.TrimSpace(*input.DisplayName)
+	}
+	if input.AvatarURL != nil {
+		updates["avatar_url"] = strings.TrimSpace(*input.AvatarURL)
+	}
+	if input.Bio != nil {
+		updates["bio"] = strings.TrimSpace(*input.Bio)
+	}
+
+	if len(updates) > 0 {
+		before := *models.NewAuthorProfile(admin)
+		if err := database.DB.Model(&admin).Updates(updates).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update profile"})
+			return
+		}
+		audit.Record(c, audit.Entry{
+			Action:     audit.ActionProfileUpdate,
+			TargetType: "admin",
+			TargetID:   admin.ID,
+			Before:     before,
+			After:      models.NewAuthorProfile(admin),
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Profile updated successfully",
+		"profile": models.NewAuthorProfile(admin),
+	})
+}
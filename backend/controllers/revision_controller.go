@@ -0,0 +1,240 @@
+package controllers
+
+import (
+	"backend/audit"
+	"backend/database"
+	"backend/markup"
+	"backend/models"
+	"backend/ogcard"
+	"backend/utils"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// GetBlogRevisions lists a blog's revisions, newest first, without
+// their content
+func GetBlogRevisions(c *gin.Context) {
+	blogID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	if err := database.DB.Select("id").First(&models.Blog{}, blogID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Blog not found"})
+		return
+	}
+
+	page, limit := parsePagination(c)
+	query := database.DB.Model(&models.BlogRevision{}).Where("blog_id = ?", blogID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch revisions"})
+		return
+	}
+
+	var revisions []models.BlogRevision
+	if err := query.Omit("content").Preload("Admin").
+		Order("number DESC").
+		Offset((page - 1) * limit).Limit(limit).
+		Find(&revisions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch revisions"})
+		return
+	}
+
+	data := make([]models.BlogRevisionResponse, 0, len(revisions))
+	for _, rev := range revisions {
+		data = append(data, models.NewBlogRevisionResponse(rev, false))
+	}
+
+	setLinkHeader(c, page, limit, total)
+	c.JSON(http.StatusOK, gin.H{
+		"data":       data,
+		"pagination": paginationMeta(page, limit, total),
+	})
+}
+
+// GetBlogRevision returns one revision with its content
+func GetBlogRevision(c *gin.Context) {
+	rev, ok := loadRevision(c, c.Param("rev"))
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, models.NewBlogRevisionResponse(rev, true))
+}
+
+// DiffBlogRevisions compares ?from=<number> with ?to=<number>, which
+// defaults to the latest revision
+func DiffBlogRevisions(c *gin.Context) {
+	if c.Query("from") == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from is required"})
+		return
+	}
+	from, ok := loadRevision(c, c.Query("from"))
+	if !ok {
+		return
+	}
+	to, ok := loadRevision(c, c.DefaultQuery("to", "latest"))
+	if !ok {
+		return
+	}
+
+	content := utils.DiffLines(from.Content, to.Content)
+	added, removed := 0, 0
+	for _, line := range content {
+		switch line.Op {
+		case utils.DiffInsert:
+			added++
+		case utils.DiffDelete:
+			removed++
+		}
+	}
+
+	fromResponse := models.NewBlogRevisionResponse(from, false)
+	toResponse := models.NewBlogRevisionResponse(to, false)
+	response := gin.H{
+		"from":    fromResponse,
+		"to":      toResponse,
+		"content": content,
+		"stats":   gin.H{"added": added, "removed": removed},
+	}
+	if from.Title != to.Title {
+		response["title"] = gin.H{"from": from.Title, "to": to.Title}
+	}
+	if !sameImage(from.Image, to.Image) {
+		response["image"] = gin.H{"from": fromResponse.Image, "to": toResponse.Image}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// RestoreBlogRevision puts a revision's title, content and image back on
+// the blog. The restore is itself saved as a new revision, so it can be
+// undone. The slug is left alone to keep links stable.
+func RestoreBlogRevision(c *gin.Context) {
+	adminID, err := getAdminID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Admin authentication required"})
+		return
+	}
+
+	rev, ok := loadRevision(c, c.Param("rev"))
+	if !ok {
+		return
+	}
+
+	var blog models.Blog
+	if err := database.DB.First(&blog, rev.BlogID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Blog not found"})
+		return
+	}
+	if !canEditContent(c, blog, adminID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Not authorized to update this blog"})
+		return
+	}
+
+	rendered, err := markup.Render(rev.Content, rev.Format)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to render revision"})
+		return
+	}
+	updates := rendered.Fields()
+	updates["title"] = rev.Title
+	updates["content"] = rev.Content
+	updates["content_format"] = rev.Format
+	updates["image"] = rev.Image
+	updates["updated_at"] = time.Now()
+
+	before := blog
+	var prunedImages []string
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := lockBlogStatus(tx, before); err != nil {
+			return err
+		}
+		if err := tx.Model(&blog).Updates(updates).Error; err != nil {
+			return err
+		}
+		if err := tx.First(&blog, blog.ID).Error; err != nil {
+			return err
+		}
+
+		prunedImages, err = database.RecordBlogRevision(tx, blog, adminID)
+		if err != nil {
+			return err
+		}
+
+		return audit.RecordTx(tx, c, audit.Entry{
+			Action:     audit.ActionBlogRestore,
+			TargetType: "blog",
+			TargetID:   blog.ID,
+			Before:     before,
+			After:      blog,
+		})
+	})
+	if errors.Is(err, errStatusChanged) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Blog status was changed by someone else; reload and try again"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to restore revision"})
+		return
+	}
+
+	removeOrphanImages(prunedImages...)
+	if cardChanged(before, blog) {
+		ogcard.Invalidate(blog.ID)
+	}
+	database.DB.Scopes(withBlogRelations).First(&blog, blog.ID)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Revision " + strconv.Itoa(rev.Number) + " restored",
+		"blog":    models.NewBlogResponse(blog),
+	})
+}
+
+// loadRevision finds a revision of the blog in the :id parameter by its
+// number, or the newest one for "latest". It writes the error response
+// itself and reports whether a revision was found.
+func loadRevision(c *gin.Context, number string) (models.BlogRevision, bool) {
+	var rev models.BlogRevision
+
+	blogID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return rev, false
+	}
+
+	query := database.DB.Preload("Admin").Where("blog_id = ?", blogID)
+	if number == "latest" {
+		query = query.Order("number DESC")
+	} else {
+		n, err := strconv.Atoi(number)
+		if err != nil || n < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid revision number"})
+			return rev, false
+		}
+		query = query.Where("number = ?", n)
+	}
+
+	if err := query.First(&rev).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Revision not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch revision"})
+		}
+		return rev, false
+	}
+	return rev, true
+}
+
+func sameImage(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
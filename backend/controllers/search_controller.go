@@ -0,0 +1,128 @@
+package controllers
+
+import (
+	"backend/database"
+	"backend/models"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	maxSearchQueryLength = 200
+	// Queries of at most this many words also match titles by trigram
+	// similarity, so "scolarship" still finds "Scholarship"
+	fuzzySearchMaxWords = 2
+	fuzzySimilarity     = 0.4
+)
+
+// searchHeadlineOptions configures the snippets returned by SearchBlogs
+const searchHeadlineOptions = "StartSel=<mark>, StopSel=</mark>, MaxWords=35, MinWords=15, MaxFragments=2, FragmentDelimiter=\" … \""
+
+// SearchBlogs runs a full-text search over published blogs with ?q=,
+// ranked with titles weighted above content. Each result carries a
+// snippet with the matches wrapped in <mark>; the snippet is built from
+// the rendered content with its HTML tags removed.
+func SearchBlogs(c *gin.Context) {
+	q := strings.TrimSpace(c.Query("q"))
+	if q == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "q is required"})
+		return
+	}
+	if utf8.RuneCountInString(q) > maxSearchQueryLength {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Search query is too long"})
+		return
+	}
+
+	page, limit := parsePagination(c)
+
+	const tsquery = "websearch_to_tsquery('english', ?)"
+	match := "search_vector @@ " + tsquery
+	matchArgs := []interface{}{q}
+	rank := "ts_rank_cd(search_vector, " + tsquery + ")"
+	rankArgs := []interface{}{q}
+	fuzzy := database.TrigramSearch && len(strings.Fields(q)) <= fuzzySearchMaxWords
+	if fuzzy {
+		// <% can use the trigram index on title, unlike comparing
+		// word_similarity against a threshold
+		match = "(" + match + " OR ? <% title)"
+		matchArgs = append(matchArgs, q)
+		rank += " + word_similarity(?, title)"
+		rankArgs = append(rankArgs, q)
+	}
+
+	var total int64
+	var hits []struct {
+		ID      uint
+		Rank    float64
+		Snippet string
+	}
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if fuzzy {
+			if err := tx.Exec("SELECT set_config('pg_trgm.word_similarity_threshold', ?, true)", strconv.FormatFloat(fuzzySimilarity, 'f', -1, 64)).Error; err != nil {
+				return err
+			}
+		}
+
+		query := tx.Model(&models.Blog{}).Scopes(publishedBlogs).Where(match, matchArgs...)
+		if tag := c.Query("tag"); tag != "" {
+			query = query.Where("id IN (?)", taggedBlogIDs(tag))
+		}
+		if err := query.Count(&total).Error; err != nil {
+			return err
+		}
+
+		selectArgs := append(rankArgs, q, searchHeadlineOptions)
+		return query.
+			Select("id, "+rank+" AS rank, "+
+				"ts_headline('english', regexp_replace(content_html, '<[^>]+>', ' ', 'g'), "+tsquery+", ?) AS snippet",
+				selectArgs...).
+			Order("rank DESC").Order("published_at DESC").
+			Offset((page - 1) * limit).Limit(limit).
+			Scan(&hits).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Search failed"})
+		return
+	}
+
+	ids := make([]uint, 0, len(hits))
+	for _, hit := range hits {
+		ids = append(ids, hit.ID)
+	}
+	var blogs []models.Blog
+	if len(ids) > 0 {
+		if err := database.DB.Scopes(withBlogRelations).Find(&blogs, ids).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Search failed"})
+			return
+		}
+	}
+	byID := make(map[uint]models.Blog, len(blogs))
+	for _, blog := range blogs {
+		byID[blog.ID] = blog
+	}
+
+	data := make([]models.BlogSearchResult, 0, len(hits))
+	for _, hit := range hits {
+		blog, ok := byID[hit.ID]
+		if !ok {
+			continue
+		}
+		data = append(data, models.BlogSearchResult{
+			BlogResponse: models.NewBlogResponse(blog),
+			Rank:         hit.Rank,
+			Snippet:      hit.Snippet,
+		})
+	}
+
+	setLinkHeader(c, page, limit, total)
+	c.JSON(http.StatusOK, gin.H{
+		"query":      q,
+		"data":       data,
+		"pagination": paginationMeta(page, limit, total),
+	})
+}
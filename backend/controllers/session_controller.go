@@ -0,0 +1,248 @@
+package controllers
+
+import (
+	"backend/database"
+	"backend/models"
+	"backend/utils"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// startSession creates a session for the admin and returns the token pair
+func startSession(c *gin.Context, admin models.Admin) (gin.H, error) {
+	refreshToken, err := utils.GenerateOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	session := models.AdminSession{
+		AdminID:          admin.ID,
+		RefreshTokenHash: utils.HashToken(refreshToken),
+		UserAgent:        truncate(c.Request.UserAgent(), 255),
+		IPAddress:        c.ClientIP(),
+		ExpiresAt:        now.Add(utils.RefreshTokenTTL()),
+		LastUsedAt:       now,
+	}
+	if err := database.DB.Create(&session).Error; err != nil {
+		return nil, err
+	}
+
+	return issueTokens(admin, session.ID, refreshToken)
+}
+
+func issueTokens(admin models.Admin, sessionID uint, refreshToken string) (gin.H, error) {
+	accessToken, err := utils.GenerateToken(admin.ID, sessionID, string(admin.Role))
+	if err != nil {
+		return nil, err
+	}
+
+	return gin.H{
+		"token":              accessToken,
+		"refresh_token":      refreshToken,
+		"expires_in":         int(utils.AccessTokenTTL().Seconds()),
+		"refresh_expires_in": int(utils.RefreshTokenTTL().Seconds()),
+	}, nil
+}
+
+// RefreshSession exchanges a refresh token for a new access token and
+// rotates the refresh token. Presenting an already rotated token is
+// treated as theft and revokes the whole session.
+func RefreshSession(c *gin.Context) {
+	var input models.RefreshRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		return
+	}
+
+	hash := utils.HashToken(input.RefreshToken)
+	now := time.Now()
+
+	var session models.AdminSession
+	err := database.DB.Where("refresh_token_hash = ?", hash).First(&session).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// Reuse of a rotated token: revoke the session it belonged to
+		database.DB.Model(&models.AdminSession{}).
+			Where("previous_token_hash = ? AND revoked_at IS NULL", hash).
+			Update("revoked_at", now)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid refresh token"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load session"})
+		return
+	}
+
+	if !session.IsActive(now) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Session has been revoked or expired"})
+		return
+	}
+
+	// Reload the admin so role changes apply from the next access token
+	var admin models.Admin
+	if err := database.DB.First(&admin, session.AdminID).Error; err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Admin not found"})
+		return
+	}
+	if admin.IsDisabled() {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Account is disabled"})
+		return
+	}
+
+	newRefreshToken, err := utils.GenerateOpaqueToken()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not generate token"})
+		return
+	}
+
+	// Conditional update so two concurrent refreshes cannot both win
+	result := database.DB.Model(&models.AdminSession{}).
+		Where("id = ? AND refresh_token_hash = ?", session.ID, hash).
+		Updates(map[string]interface{}{
+			"refresh_token_hash":  utils.HashToken(newRefreshToken),
+			"previous_token_hash": hash,
+			"last_used_at":        now,
+			"expires_at":          now.Add(utils.RefreshTokenTTL()),
+		})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to rotate session"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid refresh token"})
+		return
+	}
+
+	tokens, err := issueTokens(admin, session.ID, newRefreshToken)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not generate token"})
+		return
+	}
+
+	c.JSON(http.StatusOK, tokens)
+}
+
+// AdminLogout revokes the session the current access token belongs to
+func AdminLogout(c *gin.Context) {
+	sessionID, err := getSessionID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Admin authentication required"})
+		return
+	}
+
+	if err := database.DB.Model(&models.AdminSession{}).
+		Where("id = ? AND revoked_at IS NULL", sessionID).
+		Update("revoked_at", time.Now()).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke session"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Logged out successfully"})
+}
+
+// AdminLogoutAll revokes every active session of the current admin
+func AdminLogoutAll(c *gin.Context) {
+	adminID, err := getAdminID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Admin authentication required"})
+		return
+	}
+
+	if err := revokeSessions(database.DB, adminID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke sessions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Logged out from all devices"})
+}
+
+// revokeSessions revokes every active session of an admin
+func revokeSessions(tx *gorm.DB, adminID uint) error {
+	return tx.Model(&models.AdminSession{}).
+		Where("admin_id = ? AND revoked_at IS NULL", adminID).
+		Update("revoked_at", time.Now()).Error
+}
+
+func getSessionID(c *gin.Context) (uint, error) {
+	sessionIDValue, exists := c.Get("sessionID")
+	if !exists {
+		return 0, errors.New("sessionID not found")
+	}
+
+	sessionID, ok := sessionIDValue.(uint)
+	if !ok {
+		return 0, errors.New("invalid sessionID type")
+	}
+
+	return sessionID, nil
+}
+
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	return s[:max]
+}
+
+// VerifySession describes the session behind the presented access token
+// so the dashboard can drive its auth state from the backend
+func VerifySession(c *gin.Context) {
+	adminID, err := getAdminID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Admin authentication required"})
+		return
+	}
+	sessionID, err := getSessionID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Admin authentication required"})
+		return
+	}
+
+	var admin models.Admin
+	if err := database.DB.First(&admin, adminID).Error; err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Admin not found"})
+		return
+	}
+
+	var session models.AdminSession
+	if err := database.DB.First(&session, sessionID).Error; err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Session not found"})
+		return
+	}
+
+	now := time.Now()
+	response := gin.H{
+		"valid":   true,
+		"adminID": admin.ID,
+		"admin": gin.H{
+			"id":       admin.ID,
+			"username": admin.Username,
+			"email":    admin.Email,
+		},
+		"roles":       []string{string(admin.Role)},
+		"permissions": admin.Role.Permissions(),
+		"session": gin.H{
+			"id":           session.ID,
+			"created_at":   session.CreatedAt,
+			"last_used_at": session.LastUsedAt,
+			"expires_at":   session.ExpiresAt,
+		},
+	}
+
+	if value, ok := c.Get("tokenExpiresAt"); ok {
+		if expiresAt, ok := value.(time.Time); ok {
+			remaining := expiresAt.Sub(now)
+			if remaining < 0 {
+				remaining = 0
+			}
+			response["token_expires_at"] = expiresAt
+			response["token_expires_in"] = int(remaining.Seconds())
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,241 @@
+package controllers
+
+import (
+	"backend/config"
+	"backend/database"
+	"backend/models"
+	"backend/sitemap"
+	"database/sql"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// defaultSitePages are the website's public pages other than blog posts.
+// They mirror the routes in the frontend, including one page per entry in
+// its servicesData; SITEMAP_PAGES replaces the list when set.
+var defaultSitePages = []string{
+	"/",
+	"/about",
+	"/services",
+	"/services/education-counselling",
+	"/services/career-counselling",
+	"/services/oshc-ovhc-insurance",
+	"/services/recognition-of-prior-learning",
+	"/services/visa-485-application",
+	"/services/student-visa-assistance",
+	"/services/sop-writing-assistance",
+	"/services/flight-ticket-assistance",
+	"/starlinktravel",
+	"/contact",
+	"/blog",
+}
+
+// defaultRobotsDisallow keeps crawlers out of the admin UI and API
+var defaultRobotsDisallow = []string{"/admin", "/api/admin"}
+
+// GetSitemap serves /sitemap.xml. Small sites get a single sitemap; past
+// sitemap.MaxURLs it becomes an index of /sitemaps/<n>.xml.
+func GetSitemap(c *gin.Context) {
+	if !requireSiteURL(c) {
+		return
+	}
+	pages := sitePages()
+
+	var posts int64
+	if err := sitemapBlogs().Count(&posts).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build sitemap"})
+		return
+	}
+
+	total := int64(len(pages)) + posts
+	if total <= sitemap.MaxURLs {
+		serveSitemapChunk(c, pages, 1)
+		return
+	}
+
+	chunks := int((total + sitemap.MaxURLs - 1) / sitemap.MaxURLs)
+	base := config.PublicURL()
+	sitemaps := make([]sitemap.URL, 0, chunks)
+	for n := 1; n <= chunks; n++ {
+		lastMod, err := sitemapChunkLastMod(len(pages), n)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build sitemap"})
+			return
+		}
+		sitemaps = append(sitemaps, sitemap.URL{
+			Loc:     base + "/sitemaps/" + strconv.Itoa(n) + ".xml",
+			LastMod: lastMod,
+		})
+	}
+
+	body, err := sitemap.WriteIndex(sitemaps)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build sitemap"})
+		return
+	}
+	writeSitemap(c, body)
+}
+
+// GetSitemapChunk serves /sitemaps/<n>.xml, the nth sitemap listed by
+// the index
+func GetSitemapChunk(c *gin.Context) {
+	n, err := strconv.Atoi(strings.TrimSuffix(c.Param("file"), ".xml"))
+	if err != nil || n < 1 || !strings.HasSuffix(c.Param("file"), ".xml") {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Sitemap not found"})
+		return
+	}
+	if !requireSiteURL(c) {
+		return
+	}
+	serveSitemapChunk(c, sitePages(), n)
+}
+
+// serveSitemapChunk writes the nth run of sitemap.MaxURLs entries, taking
+// the site pages first and then published posts in ID order so the runs
+// stay stable as posts are added
+func serveSitemapChunk(c *gin.Context, pages []string, n int) {
+	start := (n - 1) * sitemap.MaxURLs
+	end := start + sitemap.MaxURLs
+	site := config.SiteURL()
+
+	latest, err := latestBlogUpdate()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build sitemap"})
+		return
+	}
+
+	urls := make([]sitemap.URL, 0)
+	for i := start; i < end && i < len(pages); i++ {
+		u := sitemap.URL{Loc: site + pages[i]}
+		if pages[i] == "/blog" {
+			u.LastMod = latest
+		}
+		urls = append(urls, u)
+	}
+
+	offset, limit := sitemapBlogWindow(len(pages), n)
+	var blogs []models.Blog
+	if err := sitemapBlogs().Select("slug", "updated_at").
+		Order("id ASC").Offset(offset).Limit(limit).
+		Find(&blogs).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build sitemap"})
+		return
+	}
+	for _, blog := range blogs {
+		urls = append(urls, sitemap.URL{Loc: site + "/blog/" + blog.Slug, LastMod: blog.UpdatedAt})
+	}
+
+	if len(urls) == 0 && n > 1 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Sitemap not found"})
+		return
+	}
+
+	body, err := sitemap.Write(urls)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build sitemap"})
+		return
+	}
+	writeSitemap(c, body)
+}
+
+// GetRobots serves /robots.txt. ROBOTS_FILE names a file to serve as is;
+// otherwise the rules disallow ROBOTS_DISALLOW (comma separated, "/" to
+// keep crawlers out entirely) and point at the sitemap when SITE_URL is set.
+func GetRobots(c *gin.Context) {
+	if file := os.Getenv("ROBOTS_FILE"); file != "" {
+		body, err := os.ReadFile(file)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read robots.txt"})
+			return
+		}
+		c.Data(http.StatusOK, "text/plain; charset=utf-8", body)
+		return
+	}
+
+	disallow := defaultRobotsDisallow
+	if value, ok := os.LookupEnv("ROBOTS_DISALLOW"); ok {
+		disallow = envList(value)
+	}
+
+	var b strings.Builder
+	b.WriteString("User-agent: *\n")
+	if len(disallow) == 0 {
+		b.WriteString("Disallow:\n")
+	}
+	for _, path := range disallow {
+		b.WriteString("Disallow: " + path + "\n")
+	}
+	if base := config.PublicURL(); base != "" {
+		b.WriteString("\nSitemap: " + base + "/sitemap.xml\n")
+	}
+
+	c.Header("Cache-Control", "public, max-age=3600")
+	c.String(http.StatusOK, b.String())
+}
+
+// sitePages returns the site paths listed ahead of the blog posts
+func sitePages() []string {
+	if value := os.Getenv("SITEMAP_PAGES"); value != "" {
+		return envList(value)
+	}
+	return defaultSitePages
+}
+
+// envList splits a comma separated setting, dropping blank entries
+func envList(value string) []string {
+	items := []string{}
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// sitemapBlogs are the published posts search engines may index
+func sitemapBlogs() *gorm.DB {
+	return database.DB.Model(&models.Blog{}).Scopes(publishedBlogs).Where("noindex = ?", false)
+}
+
+// sitemapBlogWindow returns the offset and limit into the published
+// posts covered by the nth sitemap
+func sitemapBlogWindow(pages, n int) (int, int) {
+	start := (n - 1) * sitemap.MaxURLs
+	end := start + sitemap.MaxURLs
+	if start < pages {
+		return 0, end - pages
+	}
+	return start - pages, sitemap.MaxURLs
+}
+
+// sitemapChunkLastMod is when a post in the nth sitemap last changed
+func sitemapChunkLastMod(pages, n int) (time.Time, error) {
+	offset, limit := sitemapBlogWindow(pages, n)
+	chunk := sitemapBlogs().Select("updated_at").Order("id ASC").Offset(offset).Limit(limit)
+
+	var lastMod sql.NullTime
+	if err := database.DB.Table("(?) AS chunk", chunk).
+		Select("MAX(updated_at)").Row().Scan(&lastMod); err != nil {
+		return time.Time{}, err
+	}
+	return lastMod.Time, nil
+}
+
+func latestBlogUpdate() (time.Time, error) {
+	var lastMod sql.NullTime
+	if err := sitemapBlogs().Select("MAX(updated_at)").Row().Scan(&lastMod); err != nil {
+		return time.Time{}, err
+	}
+	return lastMod.Time, nil
+}
+
+func writeSitemap(c *gin.Context, body []byte) {
+	c.Header("Cache-Control", "public, max-age=3600")
+	c.Data(http.StatusOK, "application/xml; charset=utf-8", body)
+}
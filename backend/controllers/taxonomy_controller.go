@@ -0,0 +1,641 @@
+package controllers
+
+import (
+	"backend/audit"
+	"backend/database"
+	"backend/models"
+	"backend/utils"
+	"errors"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+var (
+	errUnknownCategory = errors.New("unknown category")
+	errUnknownTag      = errors.New("unknown tag")
+	errParentNotFound  = errors.New("parent category not found")
+	errCategoryCycle   = errors.New("category cannot be nested under itself")
+	errInvalidTermSlug = errors.New("invalid term slug")
+	errTermSlugTaken   = errors.New("term slug already in use")
+)
+
+// GetTags lists every tag with the number of published posts using it
+func GetTags(c *gin.Context) {
+	var tags []models.Tag
+	if err := database.DB.Order("name ASC").Find(&tags).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch tags"})
+		return
+	}
+
+	counts, err := termPostCounts("blog_tags", "tag_id")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch tags"})
+		return
+	}
+
+	data := make([]models.TagResponse, 0, len(tags))
+	for _, tag := range tags {
+		response := models.NewTagResponse(tag)
+		count := counts[tag.ID]
+		response.PostCount = &count
+		data = append(data, response)
+	}
+	c.JSON(http.StatusOK, gin.H{"data": data})
+}
+
+// GetTagArchive returns a tag and a page of the published posts using it
+func GetTagArchive(c *gin.Context) {
+	var tag models.Tag
+	if err := database.DB.Where("slug = ?", c.Param("slug")).First(&tag).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Tag not found"})
+		return
+	}
+
+	query := database.DB.Model(&models.Blog{}).Scopes(publishedBlogs).
+		Where("id IN (?)", taggedBlogIDs(tag.Slug))
+	listBlogs(c, query, "-published_at", gin.H{"tag": models.NewTagResponse(tag)})
+}
+
+// GetCategories returns the category tree. Each category counts the
+// published posts filed directly under it.
+func GetCategories(c *gin.Context) {
+	var categories []models.Category
+	if err := database.DB.Order("name ASC").Find(&categories).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch categories"})
+		return
+	}
+
+	counts, err := termPostCounts("blog_categories", "category_id")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch categories"})
+		return
+	}
+
+	children := make(map[uint][]models.Category)
+	var roots []models.Category
+	for _, category := range categories {
+		if category.ParentID == nil {
+			roots = append(roots, category)
+		} else {
+			children[*category.ParentID] = append(children[*category.ParentID], category)
+		}
+	}
+
+	var build func([]models.Category) []models.CategoryResponse
+	build = func(nodes []models.Category) []models.CategoryResponse {
+		responses := make([]models.CategoryResponse, 0, len(nodes))
+		for _, node := range nodes {
+			response := models.NewCategoryResponse(node)
+			count := counts[node.ID]
+			response.PostCount = &count
+			response.Children = build(children[node.ID])
+			responses = append(responses, response)
+		}
+		return responses
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": build(roots)})
+}
+
+// GetCategoryArchive returns a category, its ancestors for breadcrumbs,
+// and a page of the published posts filed under it or its subcategories
+func GetCategoryArchive(c *gin.Context) {
+	var category models.Category
+	if err := database.DB.Where("slug = ?", c.Param("slug")).First(&category).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Category not found"})
+		return
+	}
+
+	ids, err := categoryTreeIDs(category.Slug)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch category"})
+		return
+	}
+
+	var ancestors []models.CategoryResponse
+	for parentID := category.ParentID; parentID != nil; {
+		var parent models.Category
+		if err := database.DB.First(&parent, *parentID).Error; err != nil {
+			break
+		}
+		ancestors = append([]models.CategoryResponse{models.NewCategoryResponse(parent)}, ancestors...)
+		parentID = parent.ParentID
+	}
+
+	query := database.DB.Model(&models.Blog{}).Scopes(publishedBlogs).
+		Where("id IN (?)", categorizedBlogIDs(ids))
+	listBlogs(c, query, "-published_at", gin.H{
+		"category":  models.NewCategoryResponse(category),
+		"ancestors": ancestors,
+	})
+}
+
+// CreateTag adds a tag; the slug defaults to one derived from the name
+func CreateTag(c *gin.Context) {
+	var input models.TagRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		return
+	}
+
+	tag := models.Tag{Name: strings.TrimSpace(input.Name)}
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		slug, err := termSlug(tx, &models.Tag{}, input.Slug, tag.Name, 0)
+		if err != nil {
+			return err
+		}
+		tag.Slug = slug
+		if err := tx.Create(&tag).Error; err != nil {
+			return err
+		}
+		return audit.RecordTx(tx, c, audit.Entry{
+			Action:     audit.ActionTagCreate,
+			TargetType: "tag",
+			TargetID:   tag.ID,
+			After:      tag,
+		})
+	})
+	if err != nil {
+		respondTermError(c, err, "Failed to create tag")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Tag created successfully",
+		"tag":     models.NewTagResponse(tag),
+	})
+}
+
+// UpdateTag renames a tag or changes its slug
+func UpdateTag(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var input models.TagRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		return
+	}
+
+	var tag models.Tag
+	if err := database.DB.First(&tag, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Tag not found"})
+		return
+	}
+
+	before := tag
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		tag.Name = strings.TrimSpace(input.Name)
+		slug, err := termSlug(tx, &models.Tag{}, input.Slug, tag.Name, tag.ID)
+		if err != nil {
+			return err
+		}
+		tag.Slug = slug
+		if err := tx.Save(&tag).Error; err != nil {
+			return err
+		}
+		return audit.RecordTx(tx, c, audit.Entry{
+			Action:     audit.ActionTagUpdate,
+			TargetType: "tag",
+			TargetID:   tag.ID,
+			Before:     before,
+			After:      tag,
+		})
+	})
+	if err != nil {
+		respondTermError(c, err, "Failed to update tag")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Tag updated successfully",
+		"tag":     models.NewTagResponse(tag),
+	})
+}
+
+// DeleteTag removes a tag from every post and deletes it
+func DeleteTag(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var tag models.Tag
+	if err := database.DB.First(&tag, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Tag not found"})
+		return
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM blog_tags WHERE tag_id = ?", tag.ID).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&tag).Error; err != nil {
+			return err
+		}
+		return audit.RecordTx(tx, c, audit.Entry{
+			Action:     audit.ActionTagDelete,
+			TargetType: "tag",
+			TargetID:   tag.ID,
+			Before:     tag,
+		})
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete tag"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Tag deleted successfully"})
+}
+
+// CreateCategory adds a category, optionally under a parent
+func CreateCategory(c *gin.Context) {
+	var input models.CategoryRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		return
+	}
+
+	category := models.Category{
+		Name:        strings.TrimSpace(input.Name),
+		Description: input.Description,
+		ParentID:    input.ParentID,
+	}
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := checkCategoryParent(tx, 0, input.ParentID); err != nil {
+			return err
+		}
+		slug, err := termSlug(tx, &models.Category{}, input.Slug, category.Name, 0)
+		if err != nil {
+			return err
+		}
+		category.Slug = slug
+		if err := tx.Create(&category).Error; err != nil {
+			return err
+		}
+		return audit.RecordTx(tx, c, audit.Entry{
+			Action:     audit.ActionCategoryCreate,
+			TargetType: "category",
+			TargetID:   category.ID,
+			After:      category,
+		})
+	})
+	if err != nil {
+		respondTermError(c, err, "Failed to create category")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":  "Category created successfully",
+		"category": models.NewCategoryResponse(category),
+	})
+}
+
+// UpdateCategory changes a category, including moving it in the tree
+func UpdateCategory(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var input models.CategoryRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		return
+	}
+
+	var category models.Category
+	if err := database.DB.First(&category, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Category not found"})
+		return
+	}
+
+	before := category
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		// Serialise moves so two concurrent ones can't form a cycle
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext('category_tree'))").Error; err != nil {
+			return err
+		}
+		if err := checkCategoryParent(tx, category.ID, input.ParentID); err != nil {
+			return err
+		}
+
+		category.Name = strings.TrimSpace(input.Name)
+		category.Description = input.Description
+		category.ParentID = input.ParentID
+		slug, err := termSlug(tx, &models.Category{}, input.Slug, category.Name, category.ID)
+		if err != nil {
+			return err
+		}
+		category.Slug = slug
+		if err := tx.Select("*").Omit("created_at").Updates(&category).Error; err != nil {
+			return err
+		}
+		return audit.RecordTx(tx, c, audit.Entry{
+			Action:     audit.ActionCategoryUpdate,
+			TargetType: "category",
+			TargetID:   category.ID,
+			Before:     before,
+			After:      category,
+		})
+	})
+	if err != nil {
+		respondTermError(c, err, "Failed to update category")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "Category updated successfully",
+		"category": models.NewCategoryResponse(category),
+	})
+}
+
+// DeleteCategory deletes a category. Its subcategories move up to its
+// parent and its posts simply lose the category.
+func DeleteCategory(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var category models.Category
+	if err := database.DB.First(&category, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Category not found"})
+		return
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext('category_tree'))").Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Category{}).Where("parent_id = ?", category.ID).
+			Update("parent_id", category.ParentID).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM blog_categories WHERE category_id = ?", category.ID).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&category).Error; err != nil {
+			return err
+		}
+		return audit.RecordTx(tx, c, audit.Entry{
+			Action:     audit.ActionCategoryDelete,
+			TargetType: "category",
+			TargetID:   category.ID,
+			Before:     category,
+		})
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete category"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Category deleted successfully"})
+}
+
+// setBlogTerms replaces a blog's tags and categories with the "tags" and
+// "categories" form fields, when the request includes them. Tags are
+// given by name and created on first use by admins who manage taxonomy;
+// categories by ID or slug.
+func setBlogTerms(c *gin.Context, tx *gorm.DB, blog *models.Blog) error {
+	if names, ok := formList(c, "tags"); ok {
+		tags, err := resolveTags(tx, names, hasPermission(c, models.PermTaxonomy))
+		if err != nil {
+			return err
+		}
+		if err := replaceAssociation(tx, blog, "Tags", tags); err != nil {
+			return err
+		}
+	}
+
+	if refs, ok := formList(c, "categories"); ok {
+		categories, err := resolveCategories(tx, refs)
+		if err != nil {
+			return err
+		}
+		if err := replaceAssociation(tx, blog, "Categories", categories); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func replaceAssociation(tx *gorm.DB, blog *models.Blog, name string, values interface{}) error {
+	association := tx.Model(blog).Association(name)
+	if err := association.Clear(); err != nil {
+		return err
+	}
+	if reflect.ValueOf(values).Len() == 0 {
+		return nil
+	}
+	return association.Append(values)
+}
+
+// formList reads a repeated form field, also splitting comma-separated
+// values. ok is false when the field was not sent at all.
+func formList(c *gin.Context, key string) ([]string, bool) {
+	values, ok := c.GetPostFormArray(key)
+	if !ok {
+		return nil, false
+	}
+
+	var items []string
+	for _, value := range values {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+	}
+	return items, true
+}
+
+// resolveTags finds the tags named, creating missing ones only when
+// create is set
+func resolveTags(tx *gorm.DB, names []string, create bool) ([]models.Tag, error) {
+	tags := []models.Tag{}
+	seen := make(map[string]bool)
+	for _, name := range names {
+		slug := utils.Slugify(name)
+		if slug == "" || seen[slug] {
+			continue
+		}
+		seen[slug] = true
+
+		var tag models.Tag
+		if !create {
+			if err := tx.Where("slug = ?", slug).First(&tag).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return nil, errUnknownTag
+				}
+				return nil, err
+			}
+		} else if err := tx.Where(models.Tag{Slug: slug}).
+			Attrs(models.Tag{Name: name}).
+			FirstOrCreate(&tag).Error; err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
+
+func resolveCategories(tx *gorm.DB, refs []string) ([]models.Category, error) {
+	categories := []models.Category{}
+	seen := make(map[uint]bool)
+	for _, ref := range refs {
+		query := tx.Where("slug = ?", ref)
+		if id, err := strconv.ParseUint(ref, 10, 64); err == nil {
+			query = tx.Where("id = ?", id)
+		}
+
+		var category models.Category
+		if err := query.First(&category).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, errUnknownCategory
+			}
+			return nil, err
+		}
+		if !seen[category.ID] {
+			seen[category.ID] = true
+			categories = append(categories, category)
+		}
+	}
+	return categories, nil
+}
+
+// termSlug settles the slug of a tag or category, which must not be used
+// by another term of the same kind
+func termSlug(tx *gorm.DB, model interface{}, rawSlug, name string, id uint) (string, error) {
+	slug := utils.Slugify(rawSlug)
+	if rawSlug == "" {
+		slug = utils.Slugify(name)
+	}
+	if slug == "" {
+		return "", errInvalidTermSlug
+	}
+
+	var count int64
+	if err := tx.Model(model).Where("slug = ? AND id <> ?", slug, id).Count(&count).Error; err != nil {
+		return "", err
+	}
+	if count > 0 {
+		return "", errTermSlugTaken
+	}
+	return slug, nil
+}
+
+// checkCategoryParent makes sure the parent exists and is not the
+// category itself or one of its descendants
+func checkCategoryParent(tx *gorm.DB, id uint, parentID *uint) error {
+	for next := parentID; next != nil; {
+		if id != 0 && *next == id {
+			return errCategoryCycle
+		}
+		var parent models.Category
+		if err := tx.Select("id", "parent_id").First(&parent, *next).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errParentNotFound
+			}
+			return err
+		}
+		next = parent.ParentID
+	}
+	return nil
+}
+
+func respondTermError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, errInvalidTermSlug):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Slug must contain letters or numbers"})
+	case errors.Is(err, errTermSlugTaken):
+		c.JSON(http.StatusConflict, gin.H{"error": "Slug is already in use"})
+	case errors.Is(err, errParentNotFound):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Parent category not found"})
+	case errors.Is(err, errCategoryCycle):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "A category cannot be nested under itself"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
+
+// termPostCounts counts published posts per term in a join table
+func termPostCounts(joinTable, column string) (map[uint]int64, error) {
+	var rows []struct {
+		TermID uint
+		Posts  int64
+	}
+	err := database.DB.Table(joinTable).
+		Select(joinTable+"."+column+" AS term_id, COUNT(*) AS posts").
+		Joins("JOIN blogs ON blogs.id = "+joinTable+".blog_id").
+		Where("blogs.status = ?", models.BlogStatusPublished).
+		Group(joinTable + "." + column).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[uint]int64, len(rows))
+	for _, row := range rows {
+		counts[row.TermID] = row.Posts
+	}
+	return counts, nil
+}
+
+// taggedBlogIDs is a subquery for the IDs of blogs with the tag slug
+func taggedBlogIDs(slug string) *gorm.DB {
+	return database.DB.Table("blog_tags").
+		Select("blog_tags.blog_id").
+		Joins("JOIN tags ON tags.id = blog_tags.tag_id").
+		Where("tags.slug = ?", slug)
+}
+
+// categorizedBlogIDs is a subquery for the IDs of blogs filed under any
+// of the categories
+func categorizedBlogIDs(categoryIDs []uint) *gorm.DB {
+	return database.DB.Table("blog_categories").
+		Select("blog_id").
+		Where("category_id IN ?", categoryIDs)
+}
+
+// categoryTreeIDs returns the ID of the category with the slug and of
+// all its descendants, or none when there is no such category
+func categoryTreeIDs(slug string) ([]uint, error) {
+	var categories []models.Category
+	if err := database.DB.Select("id", "slug", "parent_id").Find(&categories).Error; err != nil {
+		return nil, err
+	}
+
+	children := make(map[uint][]uint)
+	var root uint
+	for _, category := range categories {
+		if category.Slug == slug {
+			root = category.ID
+		}
+		if category.ParentID != nil {
+			children[*category.ParentID] = append(children[*category.ParentID], category.ID)
+		}
+	}
+	if root == 0 {
+		return []uint{}, nil
+	}
+
+	ids := []uint{root}
+	for i := 0; i < len(ids); i++ {
+		ids = append(ids, children[ids[i]]...)
+	}
+	sort.Slice(ids, func(a, b int) bool { return ids[a] < ids[b] })
+	return ids, nil
+}
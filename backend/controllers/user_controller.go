@@ -0,0 +1,410 @@
+package controllers
+
+import (
+	"backend/audit"
+	"backend/database"
+	"backend/models"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+var (
+	errAdminNotFound = errors.New("admin not found")
+	errLastOwner     = errors.New("cannot remove the last active owner")
+	errAdminHasBlogs = errors.New("admin still owns blogs")
+)
+
+// ListAdmins returns a page of admin accounts, optionally filtered by
+// ?role= and a ?q= search over username and email
+func ListAdmins(c *gin.Context) {
+	page, limit := parsePagination(c)
+
+	query := database.DB.Model(&models.Admin{})
+	if role := c.Query("role"); role != "" {
+		query = query.Where("role = ?", role)
+	}
+	if q := strings.TrimSpace(c.Query("q")); q != "" {
+		like := "%" + strings.ToLower(q) + "%"
+		query = query.Where("LOWER(username) LIKE ? OR LOWER(email) LIKE ?", like, like)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch admins"})
+		return
+	}
+
+	var admins []models.Admin
+	if err := query.Order("id ASC").Offset((page - 1) * limit).Limit(limit).Find(&admins).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch admins"})
+		return
+	}
+
+	data := make([]gin.H, 0, len(admins))
+	for _, admin := range admins {
+		data = append(data, adminSummary(admin))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":       data,
+		"pagination": paginationMeta(page, limit, total),
+	})
+}
+
+// GetAdmin returns a single admin account
+func GetAdmin(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var admin models.Admin
+	if err := database.DB.First(&admin, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Admin not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, adminSummary(admin))
+}
+
+// UpdateAdmin changes another admin's username, email or role
+func UpdateAdmin(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var input models.UpdateAdminRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		return
+	}
+	if input.Role != nil && !input.Role.IsValid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid role"})
+		return
+	}
+
+	var updated models.Admin
+	err = withLockedAdmin(uint(id), func(tx *gorm.DB, admin *models.Admin) error {
+		updates := map[string]interface{}{}
+		if input.Username != nil {
+			updates["username"] = strings.TrimSpace(*input.Username)
+		}
+		if input.Email != nil {
+			email := strings.ToLower(strings.TrimSpace(*input.Email))
+			var existing int64
+			if err := tx.Model(&models.Admin{}).Where("LOWER(email) = ? AND id <> ?", email, admin.ID).Count(&existing).Error; err != nil {
+				return err
+			}
+			if existing > 0 {
+				return errEmailRegistered
+			}
+			updates["email"] = email
+		}
+		if input.Role != nil && *input.Role != admin.Role {
+			if err := ensureOtherOwner(tx, *admin); err != nil {
+				return err
+			}
+			updates["role"] = *input.Role
+		}
+
+		if len(updates) == 0 {
+			updated = *admin
+			return nil
+		}
+
+		before := *admin
+		if err := tx.Model(admin).Updates(updates).Error; err != nil {
+			return err
+		}
+		updated = *admin
+		if err := audit.RecordTx(tx, c, audit.Entry{
+			Action:     audit.ActionAdminUpdate,
+			TargetType: "admin",
+			TargetID:   admin.ID,
+			Before:     before,
+			After:      updated,
+		}); err != nil {
+			return err
+		}
+
+		// Signed-in sessions must not keep the permissions of the old role
+		if _, roleChanged := updates["role"]; roleChanged {
+			return revokeSessions(tx, admin.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		respondAdminError(c, err, "Failed to update admin")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Admin updated successfully",
+		"admin":   adminSummary(updated),
+	})
+}
+
+// DisableAdmin blocks an account and revokes all of its sessions
+func DisableAdmin(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	err = withLockedAdmin(uint(id), func(tx *gorm.DB, admin *models.Admin) error {
+		if admin.IsDisabled() {
+			return nil
+		}
+		if err := ensureOtherOwner(tx, *admin); err != nil {
+			return err
+		}
+		before := *admin
+		if err := tx.Model(admin).Update("disabled_at", time.Now()).Error; err != nil {
+			return err
+		}
+		if err := audit.RecordTx(tx, c, audit.Entry{
+			Action:     audit.ActionAdminDisable,
+			TargetType: "admin",
+			TargetID:   admin.ID,
+			Before:     before,
+			After:      *admin,
+		}); err != nil {
+			return err
+		}
+		return revokeSessions(tx, admin.ID)
+	})
+	if err != nil {
+		respondAdminError(c, err, "Failed to disable admin")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Admin disabled successfully"})
+}
+
+// EnableAdmin lifts a previous DisableAdmin
+func EnableAdmin(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	err = withLockedAdmin(uint(id), func(tx *gorm.DB, admin *models.Admin) error {
+		before := *admin
+		if err := tx.Model(admin).Update("disabled_at", nil).Error; err != nil {
+			return err
+		}
+		return audit.RecordTx(tx, c, audit.Entry{
+			Action:     audit.ActionAdminEnable,
+			TargetType: "admin",
+			TargetID:   admin.ID,
+			Before:     before,
+			After:      *admin,
+		})
+	})
+	if err != nil {
+		respondAdminError(c, err, "Failed to enable admin")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Admin enabled successfully"})
+}
+
+// ResetAdminPassword sets a new password for another admin and signs
+// them out everywhere
+func ResetAdminPassword(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var input models.SetPasswordRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		return
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(input.Password), bcrypt.DefaultCost)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not hash password"})
+		return
+	}
+
+	err = withLockedAdmin(uint(id), func(tx *gorm.DB, admin *models.Admin) error {
+		if err := tx.Model(admin).Update("password", string(hashedPassword)).Error; err != nil {
+			return err
+		}
+		if err := audit.RecordTx(tx, c, audit.Entry{
+			Action:     audit.ActionAdminPasswordReset,
+			TargetType: "admin",
+			TargetID:   admin.ID,
+		}); err != nil {
+			return err
+		}
+		return revokeSessions(tx, admin.ID)
+	})
+	if err != nil {
+		respondAdminError(c, err, "Failed to reset password")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Password reset successfully"})
+}
+
+// DeleteAdmin removes an account. Blogs written by the admin must be
+// handed over with ?reassign_to=<admin id> before the account can go.
+func DeleteAdmin(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var reassignTo uint64
+	if value := c.Query("reassign_to"); value != "" {
+		reassignTo, err = strconv.ParseUint(value, 10, 64)
+		if err != nil || reassignTo == id {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid reassign_to"})
+			return
+		}
+	}
+
+	var blogCount int64
+	err = withLockedAdmin(uint(id), func(tx *gorm.DB, admin *models.Admin) error {
+		if err := ensureOtherOwner(tx, *admin); err != nil {
+			return err
+		}
+
+		if err := tx.Model(&models.Blog{}).Where("admin_id = ?", admin.ID).Count(&blogCount).Error; err != nil {
+			return err
+		}
+		if blogCount > 0 {
+			if reassignTo == 0 {
+				return errAdminHasBlogs
+			}
+			var target models.Admin
+			if err := tx.First(&target, reassignTo).Error; err != nil {
+				return errAdminNotFound
+			}
+			if err := tx.Model(&models.Blog{}).Where("admin_id = ?", admin.ID).Update("admin_id", target.ID).Error; err != nil {
+				return err
+			}
+		}
+
+		// Credentials go with the account; invites it sent stop working
+		// but stay on record
+		for _, model := range []interface{}{
+			&models.AdminSession{}, &models.APIKey{},
+			&models.PasswordResetToken{}, &models.RecoveryCode{},
+		} {
+			if err := tx.Where("admin_id = ?", admin.ID).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Model(&models.AdminInvite{}).
+			Where("invited_by_id = ? AND accepted_at IS NULL AND revoked_at IS NULL", admin.ID).
+			Update("revoked_at", time.Now()).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(admin).Error; err != nil {
+			return err
+		}
+		return audit.RecordTx(tx, c, audit.Entry{
+			Action:     audit.ActionAdminDelete,
+			TargetType: "admin",
+			TargetID:   admin.ID,
+			Before:     *admin,
+		})
+	})
+	if errors.Is(err, errAdminHasBlogs) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":      "Admin still owns blogs; pass reassign_to to hand them over",
+			"blog_count": blogCount,
+		})
+		return
+	}
+	if err != nil {
+		respondAdminError(c, err, "Failed to delete admin")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Admin deleted successfully"})
+}
+
+// withLockedAdmin runs fn in a transaction holding the owner lock, so
+// concurrent demotions cannot both pass the last-owner check
+func withLockedAdmin(id uint, fn func(tx *gorm.DB, admin *models.Admin) error) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext('admin_owners'))").Error; err != nil {
+			return err
+		}
+
+		var admin models.Admin
+		if err := tx.First(&admin, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errAdminNotFound
+			}
+			return err
+		}
+
+		return fn(tx, &admin)
+	})
+}
+
+// ensureOtherOwner fails when admin is the only active owner left
+func ensureOtherOwner(tx *gorm.DB, admin models.Admin) error {
+	if admin.Role != models.RoleOwner || admin.IsDisabled() {
+		return nil
+	}
+
+	var others int64
+	if err := tx.Model(&models.Admin{}).
+		Where("role = ? AND disabled_at IS NULL AND id <> ?", models.RoleOwner, admin.ID).
+		Count(&others).Error; err != nil {
+		return err
+	}
+	if others == 0 {
+		return errLastOwner
+	}
+	return nil
+}
+
+func respondAdminError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, errAdminNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Admin not found"})
+	case errors.Is(err, errLastOwner):
+		c.JSON(http.StatusConflict, gin.H{"error": "Cannot remove the last active owner"})
+	case errors.Is(err, errEmailRegistered):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Email already registered"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+	}
+}
+
+// adminSummary is the admin representation returned by the management
+// API; it never includes the password hash
+func adminSummary(admin models.Admin) gin.H {
+	return gin.H{
+		"id":          admin.ID,
+		"username":    admin.Username,
+		"email":       admin.Email,
+		"role":        admin.Role,
+		"disabled":    admin.IsDisabled(),
+		"disabled_at": admin.DisabledAt,
+		"created_at":  admin.CreatedAt,
+		"updated_at":  admin.UpdatedAt,
+	}
+}
@@ -0,0 +1,25 @@
+package database
+
+import (
+	"log"
+
+	"backend/models"
+
+	"gorm.io/gorm"
+)
+
+// publishExistingBlogs marks every post as published, dated from its
+// creation. It runs once, when the status column is first added.
+func publishExistingBlogs() error {
+	result := DB.Model(&models.Blog{}).
+		Where("status = ?", models.BlogStatusDraft).
+		Updates(map[string]interface{}{
+			"status":       models.BlogStatusPublished,
+			"published_at": gorm.Expr("created_at"),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	log.Printf("Marked %d existing blog(s) as published", result.RowsAffected)
+	return nil
+}
@@ -64,10 +64,130 @@ func ConnectDB() {
 
 	log.Println("✅ GORM connected successfully")
 
+	// Posts that predate the status column were already public
+	publishExisting := DB.Migrator().HasTable(&models.Blog{}) &&
+		!DB.Migrator().HasColumn(&models.Blog{}, "status")
+
 	// Auto-migrate models
-	if err := DB.AutoMigrate(&models.Admin{}, &models.Blog{}); err != nil {
+	if err := DB.AutoMigrate(
+		&models.Admin{},
+		&models.Blog{},
+		&models.AdminSession{},
+		&models.AdminInvite{},
+		&models.PasswordResetToken{},
+		&models.RecoveryCode{},
+		&models.LoginAttempt{},
+		&models.LoginLockout{},
+		&models.AuditEvent{},
+		&models.APIKey{},
+		&models.BlogSlugHistory{},
+		&models.BlogRevision{},
+		&models.Tag{},
+		&models.Category{},
+		&models.Comment{},
+	); err != nil {
 		log.Fatalf("AutoMigrate failed: %v", err)
 	}
+
+	if err := installAuditGuard(); err != nil {
+		log.Fatalf("Failed to install audit log guard: %v", err)
+	}
+
+	if err := backfillRenderedContent(); err != nil {
+		log.Fatalf("Failed to render blog content: %v", err)
+	}
+
+	if err := installBlogSearch(); err != nil {
+		log.Fatalf("Failed to set up blog search: %v", err)
+	}
+
+	if publishExisting {
+		if err := publishExistingBlogs(); err != nil {
+			log.Fatalf("Failed to publish existing blogs: %v", err)
+		}
+	}
+
+	if err := backfillBlogSlugs(); err != nil {
+		log.Fatalf("Failed to generate blog slugs: %v", err)
+	}
+
+	if err := normaliseAdminEmails(); err != nil {
+		log.Fatalf("Failed to normalise admin emails: %v", err)
+	}
+
+	if err := ensureOwner(); err != nil {
+		log.Fatalf("Failed to ensure an owner account: %v", err)
+	}
+}
+
+// installAuditGuard makes audit_events append-only at the database level
+func installAuditGuard() error {
+	statements := []string{
+		`CREATE OR REPLACE FUNCTION audit_events_append_only() RETURNS trigger AS $$
+		BEGIN
+			RAISE EXCEPTION 'audit_events is append-only';
+		END;
+		$$ LANGUAGE plpgsql`,
+		`DROP TRIGGER IF EXISTS audit_events_append_only ON audit_events`,
+		`CREATE TRIGGER audit_events_append_only
+			BEFORE UPDATE OR DELETE OR TRUNCATE ON audit_events
+			FOR EACH STATEMENT EXECUTE FUNCTION audit_events_append_only()`,
+	}
+	for _, stmt := range statements {
+		if err := DB.Exec(stmt).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// normaliseAdminEmails lowercases the emails of accounts created before
+// addresses were normalised and makes them unique regardless of case.
+// Addresses that clash once lowercased are left alone and reported, as
+// only an owner can tell which account should keep the address.
+func normaliseAdminEmails() error {
+	if err := DB.Exec(`UPDATE admins SET email = LOWER(TRIM(email))
+		WHERE email <> LOWER(TRIM(email))
+		AND NOT EXISTS (
+			SELECT 1 FROM admins other
+			WHERE other.id <> admins.id AND LOWER(TRIM(other.email)) = LOWER(TRIM(admins.email))
+		)`).Error; err != nil {
+		return err
+	}
+
+	var clashes []string
+	if err := DB.Model(&models.Admin{}).
+		Select("LOWER(email)").Group("LOWER(email)").Having("COUNT(*) > 1").
+		Scan(&clashes).Error; err != nil {
+		return err
+	}
+	if len(clashes) > 0 {
+		log.Printf("Warning: admin emails differ only by case, not enforcing case-insensitive uniqueness: %v", clashes)
+		return nil
+	}
+
+	return DB.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS idx_admins_email_lower ON admins (LOWER(email))`).Error
+}
+
+// ensureOwner promotes the oldest admin to owner when no owner exists,
+// so installs that predate roles keep someone able to manage the site
+func ensureOwner() error {
+	var owners int64
+	if err := DB.Model(&models.Admin{}).Where("role = ?", models.RoleOwner).Count(&owners).Error; err != nil {
+		return err
+	}
+	if owners > 0 {
+		return nil
+	}
+
+	var first models.Admin
+	err := DB.Order("id ASC").Limit(1).Find(&first).Error
+	if err != nil || first.ID == 0 {
+		return err
+	}
+
+	log.Printf("No owner account found, promoting admin %d (%s) to owner", first.ID, first.Email)
+	return DB.Model(&first).Update("role", models.RoleOwner).Error
 }
 
 func CloseDB() error {
@@ -0,0 +1,35 @@
+package database
+
+import (
+	"log"
+
+	"backend/markup"
+	"backend/models"
+)
+
+// backfillRenderedContent renders posts saved before content was
+// rendered server-side. Their content is kept as HTML, which is what the
+// frontend used to display.
+func backfillRenderedContent() error {
+	var blogs []models.Blog
+	if err := DB.Select("id", "content", "content_format").
+		Where("content_html IS NULL OR content_html = ''").
+		Find(&blogs).Error; err != nil {
+		return err
+	}
+
+	for _, blog := range blogs {
+		rendered, err := markup.Render(blog.Content, blog.ContentFormat)
+		if err != nil {
+			return err
+		}
+		if err := DB.Model(&models.Blog{}).Where("id = ?", blog.ID).Updates(rendered.Fields()).Error; err != nil {
+			return err
+		}
+	}
+
+	if len(blogs) > 0 {
+		log.Printf("Rendered content for %d existing blog(s)", len(blogs))
+	}
+	return nil
+}
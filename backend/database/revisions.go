@@ -0,0 +1,107 @@
+package database
+
+import (
+	"os"
+	"strconv"
+
+	"backend/models"
+
+	"gorm.io/gorm"
+)
+
+const defaultRevisionLimit = 50
+
+// revisionLimit is how many revisions are kept per blog, from
+// BLOG_REVISION_LIMIT
+func revisionLimit() int {
+	if limit, err := strconv.Atoi(os.Getenv("BLOG_REVISION_LIMIT")); err == nil && limit > 0 {
+		return limit
+	}
+	return defaultRevisionLimit
+}
+
+// EnsureBlogRevisions records the current state of a blog as its first
+// revision when it has none, so posts written before revisions existed
+// can be rolled back to their original text
+func EnsureBlogRevisions(tx *gorm.DB, blog models.Blog) error {
+	var count int64
+	if err := tx.Model(&models.BlogRevision{}).Where("blog_id = ?", blog.ID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	adminID := blog.AdminID
+	return tx.Create(&models.BlogRevision{
+		BlogID:    blog.ID,
+		Number:    1,
+		Title:     blog.Title,
+		Content:   blog.Content,
+		Format:    blog.ContentFormat,
+		Image:     blog.Image,
+		AdminID:   &adminID,
+		CreatedAt: blog.UpdatedAt,
+	}).Error
+}
+
+// RecordBlogRevision snapshots blog as saved by adminID and prunes the
+// oldest revisions past the retention limit. It returns the images of
+// pruned revisions so the caller can remove files nothing uses any more.
+func RecordBlogRevision(tx *gorm.DB, blog models.Blog, adminID uint) ([]string, error) {
+	// Serialise numbering per blog
+	if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext('blog_revisions'), ?::int)", blog.ID).Error; err != nil {
+		return nil, err
+	}
+
+	var latest int
+	if err := tx.Model(&models.BlogRevision{}).Where("blog_id = ?", blog.ID).
+		Select("COALESCE(MAX(number), 0)").Scan(&latest).Error; err != nil {
+		return nil, err
+	}
+
+	if err := tx.Create(&models.BlogRevision{
+		BlogID:  blog.ID,
+		Number:  latest + 1,
+		Title:   blog.Title,
+		Content: blog.Content,
+		Format:  blog.ContentFormat,
+		Image:   blog.Image,
+		AdminID: &adminID,
+	}).Error; err != nil {
+		return nil, err
+	}
+
+	var pruned []models.BlogRevision
+	if err := tx.Where("blog_id = ? AND number <= ?", blog.ID, latest+1-revisionLimit()).
+		Find(&pruned).Error; err != nil {
+		return nil, err
+	}
+	if len(pruned) == 0 {
+		return nil, nil
+	}
+
+	var images []string
+	ids := make([]uint, 0, len(pruned))
+	for _, rev := range pruned {
+		ids = append(ids, rev.ID)
+		if rev.Image != nil && *rev.Image != "" {
+			images = append(images, *rev.Image)
+		}
+	}
+	return images, tx.Delete(&models.BlogRevision{}, ids).Error
+}
+
+// ImageInUse reports whether a blog, as its image or its link preview
+// image, or any revision still points at the uploaded image
+func ImageInUse(tx *gorm.DB, image string) (bool, error) {
+	var count int64
+	if err := tx.Model(&models.Blog{}).Where("image = ? OR og_image = ?", image, image).Count(&count).Error; err != nil {
+		return false, err
+	}
+	if count > 0 {
+		return true, nil
+	}
+	err := tx.Model(&models.BlogRevision{}).Where("image = ?", image).Count(&count).Error
+	return count > 0, err
+}
@@ -0,0 +1,59 @@
+package database
+
+import (
+	"log"
+	"strings"
+)
+
+// TrigramSearch reports whether pg_trgm is installed, which search uses
+// to tolerate typos in short queries
+var TrigramSearch bool
+
+// blogSearchDocument is the text indexed for a blog: the title weighted
+// above the rendered content, with HTML tags stripped
+const blogSearchDocument = `setweight(to_tsvector('english', coalesce(title, '')), 'A') ||
+	setweight(to_tsvector('english', regexp_replace(coalesce(content_html, ''), '<[^>]+>', ' ', 'g')), 'B')`
+
+// installBlogSearch adds the generated tsvector column and its GIN index.
+// The column is managed here rather than on models.Blog so GORM never
+// tries to write to it.
+func installBlogSearch() error {
+	// A generated column can't be altered, so one built from an older
+	// document is dropped and added again
+	var current string
+	if err := DB.Raw(`SELECT pg_get_expr(d.adbin, d.adrelid)
+		FROM pg_attrdef d
+		JOIN pg_attribute a ON a.attrelid = d.adrelid AND a.attnum = d.adnum
+		WHERE d.adrelid = 'blogs'::regclass AND a.attname = 'search_vector'`).Scan(&current).Error; err != nil {
+		return err
+	}
+	if current != "" && !strings.Contains(current, "content_html") {
+		log.Println("Rebuilding blog search index")
+		if err := DB.Exec(`ALTER TABLE blogs DROP COLUMN search_vector`).Error; err != nil {
+			return err
+		}
+	}
+
+	statements := []string{
+		`ALTER TABLE blogs ADD COLUMN IF NOT EXISTS search_vector tsvector
+			GENERATED ALWAYS AS (` + blogSearchDocument + `) STORED`,
+		`CREATE INDEX IF NOT EXISTS idx_blogs_search_vector ON blogs USING GIN (search_vector)`,
+	}
+	for _, stmt := range statements {
+		if err := DB.Exec(stmt).Error; err != nil {
+			return err
+		}
+	}
+
+	// Creating an extension needs extra privileges; search still works
+	// without it, just without typo tolerance
+	if err := DB.Exec(`CREATE EXTENSION IF NOT EXISTS pg_trgm`).Error; err != nil {
+		log.Printf("pg_trgm unavailable, fuzzy search disabled: %v", err)
+		return nil
+	}
+	if err := DB.Exec(`CREATE INDEX IF NOT EXISTS idx_blogs_title_trgm ON blogs USING GIN (title gin_trgm_ops)`).Error; err != nil {
+		return err
+	}
+	TrigramSearch = true
+	return nil
+}
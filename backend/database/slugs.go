@@ -0,0 +1,121 @@
+package database
+
+import (
+	"fmt"
+	"log"
+
+	"backend/models"
+	"backend/utils"
+
+	"gorm.io/gorm"
+)
+
+// UniqueBlogSlug returns base, or base with the smallest numeric suffix,
+// that no other blog uses or redirects from. An all-digit base is prefixed
+// so the slug can't be mistaken for a blog ID.
+func UniqueBlogSlug(tx *gorm.DB, base string, blogID uint) (string, error) {
+	if base == "" {
+		base = "post"
+	} else if utils.IsNumericSlug(base) {
+		base = "post-" + base
+	}
+
+	candidate := base
+	for n := 2; ; n++ {
+		taken, err := BlogSlugTaken(tx, candidate, blogID)
+		if err != nil {
+			return "", err
+		}
+		if !taken {
+			return candidate, nil
+		}
+		candidate = fmt.Sprintf("%s-%d", base, n)
+	}
+}
+
+// BlogSlugTaken reports whether another blog uses slug, either as its
+// current slug or as an old one that still redirects
+func BlogSlugTaken(tx *gorm.DB, slug string, blogID uint) (bool, error) {
+	var count int64
+	if err := tx.Model(&models.Blog{}).Where("slug = ? AND id <> ?", slug, blogID).Count(&count).Error; err != nil {
+		return false, err
+	}
+	if count > 0 {
+		return true, nil
+	}
+
+	if err := tx.Model(&models.BlogSlugHistory{}).Where("slug = ? AND blog_id <> ?", slug, blogID).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// ChangeBlogSlug moves blog to newSlug and keeps the old slug as a redirect
+func ChangeBlogSlug(tx *gorm.DB, blog *models.Blog, newSlug string) error {
+	if blog.Slug == newSlug {
+		return nil
+	}
+
+	// Renaming a post back to one of its old slugs drops that redirect
+	if err := tx.Where("blog_id = ? AND slug = ?", blog.ID, newSlug).Delete(&models.BlogSlugHistory{}).Error; err != nil {
+		return err
+	}
+	if blog.Slug != "" {
+		if err := tx.Create(&models.BlogSlugHistory{BlogID: blog.ID, Slug: blog.Slug}).Error; err != nil {
+			return err
+		}
+	}
+	return tx.Model(blog).Update("slug", newSlug).Error
+}
+
+// backfillBlogSlugs gives posts created before slugs existed one derived
+// from their title
+func backfillBlogSlugs() error {
+	var blogs []models.Blog
+	if err := DB.Select("id", "title").Where("slug IS NULL OR slug = ''").Find(&blogs).Error; err != nil {
+		return err
+	}
+
+	for _, blog := range blogs {
+		slug, err := UniqueBlogSlug(DB, utils.Slugify(blog.Title), blog.ID)
+		if err != nil {
+			return err
+		}
+		if err := DB.Model(&models.Blog{}).Where("id = ?", blog.ID).Update("slug", slug).Error; err != nil {
+			return err
+		}
+	}
+
+	if len(blogs) > 0 {
+		log.Printf("Generated slugs for %d existing blog(s)", len(blogs))
+	}
+	return renameNumericBlogSlugs()
+}
+
+// renameNumericBlogSlugs moves posts whose slug is all digits, which no
+// route can reach, to a prefixed slug
+func renameNumericBlogSlugs() error {
+	var blogs []models.Blog
+	if err := DB.Select("id", "slug").Where("slug ~ '^[0-9]+$'").Find(&blogs).Error; err != nil {
+		return err
+	}
+
+	for i := range blogs {
+		blog := &blogs[i]
+		err := DB.Transaction(func(tx *gorm.DB) error {
+			slug, err := UniqueBlogSlug(tx, blog.Slug, blog.ID)
+			if err != nil {
+				return err
+			}
+			return ChangeBlogSlug(tx, blog, slug)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(blogs) > 0 {
+		log.Printf("Renamed %d all-digit blog slug(s)", len(blogs))
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package feed
+
+import "time"
+
+// Feed is a format-neutral syndication feed, written out by RSS, Atom
+// and JSON. Links must be absolute.
+type Feed struct {
+	Title       string
+	Description string
+	Link        string // the site the feed is about
+	FeedURL     string // where this feed is served
+	Updated     time.Time
+	Items       []Item
+}
+
+// Item is one entry in a feed
+type Item struct {
+	ID          string
+	Title       string
+	Link        string
+	Summary     string
+	ContentHTML string
+	Author      string
+	Categories  []string
+	Published   time.Time
+	Updated     time.Time
+	Image       *Image
+}
+
+// Image is an item's attached picture. Length is in bytes and may be 0
+// when unknown.
+type Image struct {
+	URL    string
+	Type   string
+	Length int64
+}
@@ -0,0 +1,78 @@
+package mailer
+
+import (
+	"context"
+	"log"
+	"os"
+	"strings"
+	"time"
+)
+
+// Message is a plain-text email
+type Message struct {
+	To      []string
+	Subject string
+	Body    string
+}
+
+// Mailer delivers outgoing email
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// Default is the mailer used by the handlers, set by InitMailer
+var Default Mailer = NewMemoryMailer()
+
+// InitMailer picks the implementation from MAIL_DRIVER (smtp, file or
+// memory). Without a driver, SMTP is used when SMTP_HOST is set and the
+// file mailer otherwise.
+func InitMailer() {
+	driver := strings.ToLower(os.Getenv("MAIL_DRIVER"))
+	if driver == "" {
+		driver = "file"
+		if os.Getenv("SMTP_HOST") != "" {
+			driver = "smtp"
+		}
+	}
+
+	switch driver {
+	case "smtp":
+		Default = &SMTPMailer{
+			Host:     os.Getenv("SMTP_HOST"),
+			Port:     envOrDefault("SMTP_PORT", "587"),
+			Username: os.Getenv("SMTP_USERNAME"),
+			Password: os.Getenv("SMTP_PASSWORD"),
+			From:     envOrDefault("MAIL_FROM", "no-reply@localhost"),
+		}
+	case "memory":
+		Default = NewMemoryMailer()
+	default:
+		dir := envOrDefault("MAIL_OUTBOX_DIR", "./mail_outbox")
+		log.Printf("Mailer: writing outgoing mail to %s", dir)
+		Default = &FileMailer{Dir: dir, From: envOrDefault("MAIL_FROM", "no-reply@localhost")}
+	}
+}
+
+// Send delivers msg through the default mailer with a bounded timeout
+func Send(msg Message) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	return Default.Send(ctx, msg)
+}
+
+// SendInBackground delivers msg without holding up the caller, so a slow
+// mail server can't stall a request. Failures are logged with what.
+func SendInBackground(msg Message, what string) {
+	go func() {
+		if err := Send(msg); err != nil {
+			log.Printf("Failed to send %s: %v", what, err)
+		}
+	}()
+}
+
+func envOrDefault(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
@@ -3,7 +3,10 @@ package main
 import (
 	"backend/config"
 	"backend/database"
+	"backend/mailer"
 	"backend/routes"
+	"backend/scheduler"
+	"backend/throttle"
 	"backend/utils"
 	"context"
 	"log"
@@ -23,7 +26,6 @@ func validateEnvVars() {
 		"ALLOWED_ORIGINS",
 		"ALLOWED_METHODS",
 		"ALLOWED_HEADERS",
-		"JWT_SECRET",
 		"DB_HOST",
 		"DB_USER",
 		"DB_PASSWORD",
@@ -35,6 +37,16 @@ func validateEnvVars() {
 			log.Fatalf("Required environment variable %s is not set", key)
 		}
 	}
+
+	// Tokens are signed either with the key ring file or the shared secret
+	if os.Getenv("JWT_KEYS_FILE") == "" && os.Getenv("JWT_SECRET") == "" {
+		log.Fatal("Either JWT_KEYS_FILE or JWT_SECRET must be set")
+	}
+
+	// Absolute links are never taken from request headers
+	if config.SiteURL() == "" {
+		log.Println("Warning: SITE_URL is not set; feeds and sitemaps are unavailable and emails carry no links")
+	}
 }
 
 func cleanupOrigins(origins []string) []string {
@@ -65,9 +77,15 @@ func main() {
 		}
 	}()
 
+	// Initialize login throttling counters
+	throttle.InitStore()
+
 	// Initialize JWT
 	utils.InitJWT()
 
+	// Initialize outgoing mail
+	mailer.InitMailer()
+
 	// Set Gin mode based on environment
 	if os.Getenv("GIN_MODE") == "release" {
 		gin.SetMode(gin.ReleaseMode)
@@ -149,7 +167,11 @@ func main() {
 			"routes": gin.H{
 				"health":  "/health",
 				"admin":   "/api/admin",
+				"search":  "/api/blogs/search",
 				"uploads": "/uploads",
+				"feeds":   "/feeds/blog.rss",
+				"sitemap": "/sitemap.xml",
+				"jwks":    "/.well-known/jwks.json",
 				"swagger": "/swagger/index.html",
 			},
 		})
@@ -188,8 +210,12 @@ func main() {
 	api := router.Group("/api")
 	{
 		routes.AdminRoutes(api)
+		routes.BlogRoutes(api)
 		// Add other route groups here
 	}
+	routes.WellKnownRoutes(router)
+	routes.FeedRoutes(router)
+	routes.SitemapRoutes(router)
 
 	// Handle OPTIONS for all routes
 	router.OPTIONS("/*any", func(c *gin.Context) {
@@ -221,6 +247,14 @@ func main() {
 		Handler: router,
 	}
 
+	// Background jobs; safe to run on every replica
+	jobs := scheduler.New(scheduler.Job{
+		Name:     "publish-scheduled-blogs",
+		Interval: scheduler.IntervalFromEnv("SCHEDULER_INTERVAL", time.Minute),
+		Run:      scheduler.PublishDueBlogs,
+	})
+	jobs.Start()
+
 	// Graceful shutdown
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
@@ -240,6 +274,9 @@ func main() {
 	if err := server.Shutdown(ctx); err != nil {
 		log.Fatal("Server forced to shutdown:", err)
 	}
+	if err := jobs.Stop(ctx); err != nil {
+		log.Printf("Background jobs did not stop in time: %v", err)
+	}
 
 	log.Println("Server exited properly")
 }
@@ -0,0 +1,120 @@
+package markup
+
+import (
+	"bytes"
+	"html"
+	"math"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	goldmarkhtml "github.com/yuin/goldmark/renderer/html"
+)
+
+// Formats blog content can be written in
+const (
+	FormatMarkdown = "markdown"
+	FormatHTML     = "html"
+)
+
+const (
+	excerptLength  = 200
+	wordsPerMinute = 200
+)
+
+// Rendered is blog content turned into safe HTML, with the fields
+// derived from its text
+type Rendered struct {
+	HTML        string
+	Excerpt     string
+	WordCount   int
+	ReadingTime int // minutes
+}
+
+// Markdown is rendered with GitHub extensions. Raw HTML is passed
+// through because the sanitizer runs afterwards.
+var markdown = goldmark.New(
+	goldmark.WithExtensions(extension.GFM),
+	goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+	goldmark.WithRendererOptions(goldmarkhtml.WithUnsafe()),
+)
+
+// policy is the allowlist for post HTML: user-generated-content markup
+// plus language classes on code blocks. Links may be followed since
+// posts are written by our own staff.
+var policy = func() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+	p.RequireNoFollowOnLinks(false)
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^language-[\w-]+$`)).OnElements("code")
+	p.AllowAttrs("id").Matching(bluemonday.SpaceSeparatedTokens).OnElements("h1", "h2", "h3", "h4", "h5", "h6")
+	return p
+}()
+
+// textPolicy strips every tag, leaving the text of a post
+var textPolicy = bluemonday.StrictPolicy()
+
+// IsValidFormat reports whether format is a known content format
+func IsValidFormat(format string) bool {
+	return format == FormatMarkdown || format == FormatHTML
+}
+
+// Render converts source to sanitized HTML. Unknown formats are treated
+// as HTML, which is sanitized all the same.
+func Render(source, format string) (Rendered, error) {
+	unsafe := source
+	if format == FormatMarkdown {
+		var buf bytes.Buffer
+		if err := markdown.Convert([]byte(source), &buf); err != nil {
+			return Rendered{}, err
+		}
+		unsafe = buf.String()
+	}
+
+	safe := policy.Sanitize(unsafe)
+	text := PlainText(safe)
+	words := len(strings.Fields(text))
+
+	return Rendered{
+		HTML:        safe,
+		Excerpt:     Excerpt(text, excerptLength),
+		WordCount:   words,
+		ReadingTime: int(math.Max(1, math.Ceil(float64(words)/wordsPerMinute))),
+	}, nil
+}
+
+// PlainText returns the text of an HTML fragment with tags removed,
+// entities decoded and whitespace collapsed
+func PlainText(fragment string) string {
+	// Keep words in neighbouring blocks apart once the tags are gone
+	spaced := strings.NewReplacer("<", " <", ">", "> ").Replace(fragment)
+	return strings.Join(strings.Fields(html.UnescapeString(textPolicy.Sanitize(spaced))), " ")
+}
+
+// Excerpt shortens text to at most max characters, cutting at a word
+// boundary and adding an ellipsis when anything was cut
+func Excerpt(text string, max int) string {
+	if utf8.RuneCountInString(text) <= max {
+		return text
+	}
+
+	runes := []rune(text)
+	cut := string(runes[:max])
+	if i := strings.LastIndex(cut, " "); i > max/2 {
+		cut = cut[:i]
+	}
+	return strings.TrimRight(cut, " ,.;:-") + "…"
+}
+
+// Fields returns the derived blog columns for an update
+func (r Rendered) Fields() map[string]interface{} {
+	return map[string]interface{}{
+		"content_html": r.HTML,
+		"excerpt":      r.Excerpt,
+		"word_count":   r.WordCount,
+		"reading_time": r.ReadingTime,
+	}
+}
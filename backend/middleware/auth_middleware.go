@@ -1,12 +1,17 @@
 package middleware
 
 import (
+	"backend/database"
+	"backend/models"
 	"backend/utils"
+	"errors"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt"
 )
 
 func RequireAuth(c *gin.Context) {
@@ -16,6 +21,12 @@ func RequireAuth(c *gin.Context) {
 		return
 	}
 
+	// Scripts authenticate with "Authorization: ApiKey <key>"
+	if key, ok := strings.CutPrefix(authHeader, "ApiKey "); ok {
+		authenticateAPIKey(c, strings.TrimSpace(key))
+		return
+	}
+
 	tokenString := strings.TrimPrefix(authHeader, "Bearer ")
 	if tokenString == authHeader {
 		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Bearer token required"})
@@ -28,36 +39,157 @@ func RequireAuth(c *gin.Context) {
 		return
 	}
 
+	// MFA challenge tokens only unlock the second login step
+	if _, ok := claims["purpose"]; ok {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Access token required"})
+		return
+	}
+
 	// Safely extract adminID from claims
-	adminIDValue, ok := claims["admin_id"]
-	if !ok {
-		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Token missing admin_id"})
+	adminID, err := uintClaim(claims, "admin_id")
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid admin_id in token: " + err.Error()})
+		return
+	}
+
+	// Every access token is bound to a server-side session
+	sessionID, err := uintClaim(claims, "sid")
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid sid in token: " + err.Error()})
+		return
+	}
+
+	// Only sessions of accounts that are still enabled count. The role is
+	// read from the account rather than the token so a role change takes
+	// effect immediately.
+	var session struct {
+		models.AdminSession
+		AdminRole models.Role
+	}
+	result := database.DB.Model(&models.AdminSession{}).
+		Select("admin_sessions.*, admins.role AS admin_role").
+		Joins("JOIN admins ON admins.id = admin_sessions.admin_id AND admins.disabled_at IS NULL").
+		Where("admin_sessions.id = ?", sessionID).
+		Limit(1).
+		Scan(&session)
+	if result.Error != nil || result.RowsAffected == 0 {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Session not found or account disabled"})
+		return
+	}
+
+	role := session.AdminRole
+	if !role.IsValid() {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid role on account"})
+		return
+	}
+
+	if session.AdminID != adminID || !session.IsActive(time.Now()) {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Session has been revoked or expired"})
+		return
+	}
+
+	c.Set("adminID", adminID)
+	c.Set("sessionID", sessionID)
+	c.Set("adminRole", role)
+	if exp, ok := claims["exp"].(float64); ok {
+		c.Set("tokenExpiresAt", time.Unix(int64(exp), 0))
+	}
+	c.Next()
+}
+
+// authenticateAPIKey resolves an API key to its admin. The key acts with
+// the admin's role, narrowed to the scopes it was minted with.
+func authenticateAPIKey(c *gin.Context, key string) {
+	var apiKey models.APIKey
+	if err := database.DB.Where("key_hash = ?", utils.HashToken(key)).First(&apiKey).Error; err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid API key"})
+		return
+	}
+
+	now := time.Now()
+	if !apiKey.IsActive(now) {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "API key has been revoked or expired"})
 		return
 	}
 
-	// Convert to uint properly
-	var adminID uint
-	switch v := adminIDValue.(type) {
+	var admin models.Admin
+	if err := database.DB.First(&admin, apiKey.AdminID).Error; err != nil || admin.IsDisabled() {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Admin not found or account disabled"})
+		return
+	}
+
+	// Record usage at most once a minute to keep writes off the hot path
+	database.DB.Model(&models.APIKey{}).
+		Where("id = ? AND (last_used_at IS NULL OR last_used_at < ?)", apiKey.ID, now.Add(-time.Minute)).
+		Updates(map[string]interface{}{"last_used_at": now, "last_used_ip": c.ClientIP()})
+
+	c.Set("adminID", admin.ID)
+	c.Set("adminRole", admin.Role)
+	c.Set("apiKeyID", apiKey.ID)
+	c.Set("apiKeyScopes", apiKey.ScopeList())
+	c.Next()
+}
+
+// RequireSession rejects API keys on routes meant for people signed in
+// through the dashboard, such as account and key management
+func RequireSession(c *gin.Context) {
+	if _, isAPIKey := c.Get("apiKeyID"); isAPIKey {
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "This endpoint cannot be used with an API key"})
+		return
+	}
+	c.Next()
+}
+
+// RequirePermission aborts with 403 unless the authenticated admin's role
+// grants the permission, and for API keys, one of the key's scopes does
+// too. It must run after RequireAuth.
+func RequirePermission(permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		value, exists := c.Get("adminRole")
+		role, ok := value.(models.Role)
+		if !exists || !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Admin authentication required"})
+			return
+		}
+
+		allowed := role.Can(permission)
+		if scopes, isAPIKey := c.Get("apiKeyScopes"); isAPIKey {
+			list, _ := scopes.([]string)
+			allowed = allowed && models.ScopesGrant(list, permission)
+		}
+
+		if !allowed {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error":      "Insufficient permissions",
+				"permission": permission,
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// uintClaim converts a numeric claim to uint regardless of how the
+// JSON decoder represented it
+func uintClaim(claims jwt.MapClaims, key string) (uint, error) {
+	value, ok := claims[key]
+	if !ok {
+		return 0, errors.New("claim missing")
+	}
+
+	switch v := value.(type) {
 	case float64:
-		adminID = uint(v)
+		return uint(v), nil
 	case int:
-		adminID = uint(v)
+		return uint(v), nil
 	case string:
 		id, err := strconv.ParseUint(v, 10, 64)
 		if err != nil {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
-				"error": "Invalid admin_id format in token",
-			})
-			return
+			return 0, errors.New("invalid format")
 		}
-		adminID = uint(id)
+		return uint(id), nil
 	default:
-		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
-			"error": "Unsupported admin_id type in token",
-		})
-		return
+		return 0, errors.New("unsupported type")
 	}
-
-	c.Set("adminID", adminID)
-	c.Next()
 }
@@ -1,13 +1,75 @@
 package models
 
+import "time"
+
 type Admin struct {
-	ID       uint   `gorm:"primaryKey;autoIncrement" json:"id"`
-	Username string `gorm:"size:100;not null" json:"username"`
-	Email    string `gorm:"size:100;not null;unique" json:"email"`
-	Password string `gorm:"size:255;not null" json:"password"`
+	ID         uint       `gorm:"primaryKey;autoIncrement" json:"id"`
+	Username   string     `gorm:"size:100;not null" json:"username"`
+	Email      string     `gorm:"size:100;not null;unique" json:"email"`
+	Password   string     `gorm:"size:255;not null" json:"-"`
+	Role       Role       `gorm:"size:20;not null;default:author" json:"role"`
+	DisabledAt *time.Time `json:"disabled_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+
+	// Public author profile shown on blog posts
+	DisplayName string `gorm:"size:100" json:"display_name"`
+	AvatarURL   string `gorm:"size:512" json:"avatar_url"`
+	Bio         string `gorm:"type:text" json:"bio"`
+
+	// Two-factor authentication; the secret is encrypted at rest and
+	// TOTPLastStep stops a code from being replayed
+	TOTPSecret    string     `gorm:"size:255" json:"-"`
+	TOTPEnabledAt *time.Time `json:"totp_enabled_at"`
+	TOTPLastStep  int64      `json:"-"`
+}
+
+// MFAEnabled reports whether the account requires a TOTP code at login
+func (a *Admin) MFAEnabled() bool {
+	return a.TOTPEnabledAt != nil
+}
+
+// IsDisabled reports whether the account has been disabled by an owner
+func (a *Admin) IsDisabled() bool {
+	return a.DisabledAt != nil
 }
 
 type LoginRequest struct {
 	Email    string `json:"email" binding:"required,email"`
 	Password string `json:"password" binding:"required,min=6"`
 }
+
+// SignupRequest registers an account from an invite token
+type SignupRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Username string `json:"username" binding:"required,max=100"`
+	Password string `json:"password" binding:"required,min=8"`
+}
+
+// BootstrapRequest creates the very first owner account
+type BootstrapRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Username string `json:"username" binding:"required,max=100"`
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required,min=8"`
+}
+
+// UpdateAdminRequest carries the fields an owner may change on another
+// admin; nil fields are left untouched
+type UpdateAdminRequest struct {
+	Username *string `json:"username" binding:"omitempty,max=100"`
+	Email    *string `json:"email" binding:"omitempty,email"`
+	Role     *Role   `json:"role"`
+}
+
+// SetPasswordRequest sets a new password for an admin
+type SetPasswordRequest struct {
+	Password string `json:"password" binding:"required,min=8"`
+}
+
+// UpdateProfileRequest changes the signed-in admin's public profile
+type UpdateProfileRequest struct {
+	DisplayName *string `json:"display_name" binding:"omitempty,max=100"`
+	AvatarURL   *string `json:"avatar_url" binding:"omitempty,max=512,url"`
+	Bio         *string `json:"bio" binding:"omitempty,max=2000"`
+}
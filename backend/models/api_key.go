@@ -0,0 +1,74 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// Scopes an API key can be limited to
+const (
+	ScopeBlogsRead  = "blogs:read"
+	ScopeBlogsWrite = "blogs:write"
+)
+
+var scopePermissions = map[string][]string{
+	ScopeBlogsRead: {PermBlogRead},
+	ScopeBlogsWrite: {
+		PermBlogCreate,
+		PermBlogUpdateOwn, PermBlogUpdateAny,
+		PermBlogDeleteOwn, PermBlogDeleteAny,
+		PermBlogPublish, PermTaxonomy,
+		PermComments,
+	},
+}
+
+// APIKey lets scripts call the admin API on behalf of an admin. Only the
+// SHA-256 hash of the key is stored; Prefix identifies it in listings.
+type APIKey struct {
+	ID         uint       `gorm:"primaryKey;autoIncrement" json:"id"`
+	AdminID    uint       `gorm:"not null;index" json:"admin_id"`
+	Name       string     `gorm:"size:100;not null" json:"name"`
+	Prefix     string     `gorm:"size:16;not null" json:"prefix"`
+	KeyHash    string     `gorm:"size:64;not null;uniqueIndex" json:"-"`
+	Scopes     string     `gorm:"size:255;not null" json:"-"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	LastUsedIP string     `gorm:"size:64" json:"last_used_ip"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// ScopeList returns the scopes granted to the key
+func (k *APIKey) ScopeList() []string {
+	return strings.Fields(k.Scopes)
+}
+
+// IsActive reports whether the key can still be used
+func (k *APIKey) IsActive(now time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || now.Before(*k.ExpiresAt))
+}
+
+// IsValidScope reports whether scope is a known API key scope
+func IsValidScope(scope string) bool {
+	_, ok := scopePermissions[scope]
+	return ok
+}
+
+// ScopesGrant reports whether any of the scopes includes the permission
+func ScopesGrant(scopes []string, permission string) bool {
+	for _, scope := range scopes {
+		for _, p := range scopePermissions[scope] {
+			if p == permission {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// CreateAPIKeyRequest represents the body of an API key creation call
+type CreateAPIKeyRequest struct {
+	Name          string   `json:"name" binding:"required,max=100"`
+	Scopes        []string `json:"scopes" binding:"required,min=1"`
+	ExpiresInDays int      `json:"expires_in_days" binding:"omitempty,min=1,max=365"`
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// AuditEvent is one row of the append-only audit log. Updates and
+// deletes are rejected by a database trigger.
+type AuditEvent struct {
+	ID           uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	ActorAdminID *uint     `gorm:"index" json:"actor_admin_id"`
+	Action       string    `gorm:"size:64;not null;index" json:"action"`
+	TargetType   string    `gorm:"size:64;index:idx_audit_target" json:"target_type"`
+	TargetID     string    `gorm:"size:64;index:idx_audit_target" json:"target_id"`
+	Before       JSON      `gorm:"type:jsonb" json:"before"`
+	After        JSON      `gorm:"type:jsonb" json:"after"`
+	Changes      JSON      `gorm:"type:jsonb" json:"changes"`
+	IPAddress    string    `gorm:"size:64" json:"ip_address"`
+	UserAgent    string    `gorm:"size:255" json:"user_agent"`
+	CreatedAt    time.Time `gorm:"index" json:"created_at"`
+}
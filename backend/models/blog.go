@@ -2,13 +2,93 @@ package models
 
 import "time"
 
+// BlogStatus is where a post is in the editorial workflow. Only
+// published posts are visible on the public endpoints.
+type BlogStatus string
+
+const (
+	BlogStatusDraft     BlogStatus = "draft"
+	BlogStatusReview    BlogStatus = "review"
+	BlogStatusPublished BlogStatus = "published"
+	BlogStatusArchived  BlogStatus = "archived"
+)
+
+// blogTransitions lists the statuses a post may move to from each status
+var blogTransitions = map[BlogStatus][]BlogStatus{
+	BlogStatusDraft:     {BlogStatusReview, BlogStatusPublished},
+	BlogStatusReview:    {BlogStatusDraft, BlogStatusPublished},
+	BlogStatusPublished: {BlogStatusDraft, BlogStatusArchived},
+	BlogStatusArchived:  {BlogStatusDraft, BlogStatusPublished},
+}
+
+// IsValid reports whether s is one of the known statuses
+func (s BlogStatus) IsValid() bool {
+	_, ok := blogTransitions[s]
+	return ok
+}
+
+// CanTransitionTo reports whether a post in status s may move to next
+func (s BlogStatus) CanTransitionTo(next BlogStatus) bool {
+	for _, allowed := range blogTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// IsPublic reports whether readers can see, or have seen, posts in
+// status s. Moving a post into or out of these needs blog:publish.
+func (s BlogStatus) IsPublic() bool {
+	return s == BlogStatusPublished || s == BlogStatusArchived
+}
+
 type Blog struct {
-	ID        uint      `gorm:"primaryKey;autoIncrement" json:"id"`
-	Title     string    `gorm:"size:255;not null" json:"title"`
-	Content   string    `gorm:"type:text;not null" json:"content"`
-	Image     *string   `json:"image"`
-	AdminID   uint      `gorm:"not null" json:"admin_id"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
-	Admin     Admin     `gorm:"foreignKey:AdminID"`
+	ID      uint   `gorm:"primaryKey;autoIncrement" json:"id"`
+	Title   string `gorm:"size:255;not null" json:"title"`
+	Slug    string `gorm:"size:255;uniqueIndex" json:"slug"`
+	Content string `gorm:"type:text;not null" json:"content"`
+	// ContentFormat says how Content is written; the fields after it are
+	// derived from Content on every save
+	ContentFormat string  `gorm:"size:20;not null;default:'html'" json:"content_format"`
+	ContentHTML   string  `gorm:"type:text" json:"content_html"`
+	Excerpt       string  `gorm:"type:text" json:"excerpt"`
+	WordCount     int     `gorm:"not null;default:0" json:"word_count"`
+	ReadingTime   int     `gorm:"not null;default:0" json:"reading_time"`
+	Image         *string `json:"image"`
+	// Search engine and link preview overrides; blank ones fall back to
+	// the post's own title, excerpt and image
+	MetaTitle       string     `gorm:"size:70" json:"meta_title"`
+	MetaDescription string     `gorm:"size:160" json:"meta_description"`
+	CanonicalURL    string     `gorm:"size:2048" json:"canonical_url"`
+	OGImage         *string    `gorm:"column:og_image" json:"og_image"`
+	NoIndex         bool       `gorm:"column:noindex;not null;default:false" json:"noindex"`
+	Status          BlogStatus `gorm:"size:20;not null;default:'draft';index" json:"status"`
+	PublishedAt     *time.Time `gorm:"index" json:"published_at"`
+	PublishAt       *time.Time `gorm:"index" json:"publish_at"`
+	AdminID         uint       `gorm:"not null" json:"admin_id"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+	Admin           Admin      `gorm:"foreignKey:AdminID" json:"-"`
+	Tags            []Tag      `gorm:"many2many:blog_tags" json:"-"`
+	Categories      []Category `gorm:"many2many:blog_categories" json:"-"`
+}
+
+const (
+	// MaxMetaTitleLength and MaxMetaDescriptionLength are counted in
+	// characters, around where search results truncate
+	MaxMetaTitleLength       = 70
+	MaxMetaDescriptionLength = 160
+	MaxCanonicalURLLength    = 2048
+)
+
+// BlogStatusRequest represents a request to move a blog to a new status
+type BlogStatusRequest struct {
+	Status BlogStatus `json:"status" binding:"required"`
+	Note   string     `json:"note" binding:"max=1000"`
+}
+
+// ScheduleBlogRequest represents a request to publish a blog later
+type ScheduleBlogRequest struct {
+	PublishAt time.Time `json:"publish_at" binding:"required"`
 }
@@ -0,0 +1,162 @@
+package models
+
+import (
+	"strconv"
+	"time"
+
+	"backend/config"
+)
+
+// AuthorProfile is the public view of an admin shown next to their posts
+type AuthorProfile struct {
+	ID          uint   `json:"id"`
+	DisplayName string `json:"display_name"`
+	AvatarURL   string `json:"avatar_url,omitempty"`
+	Bio         string `json:"bio,omitempty"`
+}
+
+// BlogResponse is the API representation of a blog post. Handlers return
+// this rather than Blog so GORM associations never leak into responses.
+type BlogResponse struct {
+	ID              uint               `json:"id"`
+	Title           string             `json:"title"`
+	Slug            string             `json:"slug"`
+	Content         string             `json:"content"`
+	Format          string             `json:"content_format"`
+	ContentHTML     string             `json:"content_html"`
+	Excerpt         string             `json:"excerpt"`
+	WordCount       int                `json:"word_count"`
+	ReadingTime     int                `json:"reading_time"`
+	Image           *string            `json:"image"`
+	MetaTitle       string             `json:"meta_title"`
+	MetaDescription string             `json:"meta_description"`
+	CanonicalURL    string             `json:"canonical_url"`
+	OGImage         *string            `json:"og_image"`
+	NoIndex         bool               `json:"noindex"`
+	SEO             BlogSEO            `json:"seo"`
+	Status          BlogStatus         `json:"status"`
+	PublishedAt     *time.Time         `json:"published_at"`
+	PublishAt       *time.Time         `json:"publish_at"`
+	AdminID         uint               `json:"admin_id"`
+	Author          *AuthorProfile     `json:"author,omitempty"`
+	Tags            []TagResponse      `json:"tags"`
+	Categories      []CategoryResponse `json:"categories"`
+	CreatedAt       time.Time          `json:"created_at"`
+	UpdatedAt       time.Time          `json:"updated_at"`
+}
+
+// BlogSEO is what a page showing the post should put in its head tags,
+// with the post's overrides already resolved against its defaults. The
+// preview image defaults to the card drawn by GET /api/blogs/:id/og.png,
+// and is absolute once PUBLIC_URL or SITE_URL is configured.
+type BlogSEO struct {
+	Title        string  `json:"title"`
+	Description  string  `json:"description"`
+	CanonicalURL string  `json:"canonical_url,omitempty"`
+	OGImage      *string `json:"og_image"`
+	NoIndex      bool    `json:"noindex"`
+}
+
+// NewAuthorProfile builds the public profile of an admin
+func NewAuthorProfile(admin Admin) *AuthorProfile {
+	if admin.ID == 0 {
+		return nil
+	}
+	name := admin.DisplayName
+	if name == "" {
+		name = admin.Username
+	}
+	return &AuthorProfile{
+		ID:          admin.ID,
+		DisplayName: name,
+		AvatarURL:   admin.AvatarURL,
+		Bio:         admin.Bio,
+	}
+}
+
+// NewBlogResponse builds the API representation of blog. The author,
+// tags and categories are included when they have been preloaded.
+func NewBlogResponse(blog Blog) BlogResponse {
+	response := BlogResponse{
+		ID:              blog.ID,
+		Title:           blog.Title,
+		Slug:            blog.Slug,
+		Content:         blog.Content,
+		Format:          blog.ContentFormat,
+		ContentHTML:     blog.ContentHTML,
+		Excerpt:         blog.Excerpt,
+		WordCount:       blog.WordCount,
+		ReadingTime:     blog.ReadingTime,
+		MetaTitle:       blog.MetaTitle,
+		MetaDescription: blog.MetaDescription,
+		CanonicalURL:    blog.CanonicalURL,
+		NoIndex:         blog.NoIndex,
+		Status:          blog.Status,
+		PublishedAt:     blog.PublishedAt,
+		PublishAt:       blog.PublishAt,
+		AdminID:         blog.AdminID,
+		Author:          NewAuthorProfile(blog.Admin),
+		Tags:            make([]TagResponse, 0, len(blog.Tags)),
+		Categories:      make([]CategoryResponse, 0, len(blog.Categories)),
+		CreatedAt:       blog.CreatedAt,
+		UpdatedAt:       blog.UpdatedAt,
+	}
+	for _, tag := range blog.Tags {
+		response.Tags = append(response.Tags, NewTagResponse(tag))
+	}
+	for _, category := range blog.Categories {
+		response.Categories = append(response.Categories, NewCategoryResponse(category))
+	}
+	response.Image = uploadPath(blog.Image)
+	response.OGImage = uploadPath(blog.OGImage)
+
+	response.SEO = BlogSEO{
+		Title:        blog.MetaTitle,
+		Description:  blog.MetaDescription,
+		CanonicalURL: blog.CanonicalURL,
+		OGImage:      response.OGImage,
+		NoIndex:      blog.NoIndex,
+	}
+	if response.SEO.Title == "" {
+		response.SEO.Title = blog.Title
+	}
+	if response.SEO.Description == "" {
+		response.SEO.Description = blog.Excerpt
+	}
+	if response.SEO.OGImage == nil && blog.ID != 0 {
+		card := "/api/blogs/" + strconv.FormatUint(uint64(blog.ID), 10) + "/og.png"
+		response.SEO.OGImage = &card
+	}
+	// Link preview consumers only follow absolute URLs
+	if base := config.PublicURL(); base != "" && response.SEO.OGImage != nil {
+		image := base + *response.SEO.OGImage
+		response.SEO.OGImage = &image
+	}
+	return response
+}
+
+// uploadPath returns where an uploaded file is served, or nil for none
+func uploadPath(file *string) *string {
+	if file == nil || *file == "" {
+		return nil
+	}
+	path := "/uploads/" + *file
+	return &path
+}
+
+// NewBlogResponses maps a slice of blogs to their API representation
+func NewBlogResponses(blogs []Blog) []BlogResponse {
+	responses := make([]BlogResponse, 0, len(blogs))
+	for _, blog := range blogs {
+		responses = append(responses, NewBlogResponse(blog))
+	}
+	return responses
+}
+
+// BlogSearchResult is a blog matched by a search, with its relevance and
+// a highlighted snippet of the matching text
+type BlogSearchResult struct {
+	BlogResponse
+	Rank    float64 `json:"rank"`
+	Snippet string  `json:"snippet"`
+}
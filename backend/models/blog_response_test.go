@@ -0,0 +1,120 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	testPasswordHash = "$2a$10$abcdefghijklmnopqrstuvSECRETHASHxyz0123456789ABCDEFGH"
+	testEmail        = "author@example.com"
+	testTOTPSecret   = "ENCRYPTED-TOTP-SECRET-VALUE"
+)
+
+// sensitiveAdmin is an author with every private field filled in
+func sensitiveAdmin() Admin {
+	enabled := time.Now()
+	return Admin{
+		ID:            7,
+		Username:      "author",
+		Email:         testEmail,
+		Password:      testPasswordHash,
+		Role:          RoleAuthor,
+		DisplayName:   "Jane Author",
+		AvatarURL:     "https://example.com/avatar.png",
+		Bio:           "Writes about visas",
+		TOTPSecret:    testTOTPSecret,
+		TOTPEnabledAt: &enabled,
+		TOTPLastStep:  123456,
+	}
+}
+
+func sensitiveBlog() Blog {
+	image := "cover.png"
+	return Blog{
+		ID:      1,
+		Title:   "Student visas",
+		Slug:    "student-visas",
+		Content: "Body",
+		Image:   &image,
+		Status:  BlogStatusPublished,
+		AdminID: 7,
+		Admin:   sensitiveAdmin(),
+	}
+}
+
+// assertNoSensitiveData fails when the JSON has a key naming a private
+// field or carries one of the private values
+func assertNoSensitiveData(t *testing.T, v interface{}) {
+	t.Helper()
+
+	body, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+
+	for _, value := range []string{testPasswordHash, testEmail, testTOTPSecret} {
+		if strings.Contains(string(body), value) {
+			t.Errorf("response contains private value %q: %s", value, body)
+		}
+	}
+
+	var decoded interface{}
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	walkKeys(decoded, func(key string) {
+		lower := strings.ToLower(key)
+		for _, banned := range []string{"password", "email", "totp"} {
+			if strings.Contains(lower, banned) {
+				t.Errorf("response has private key %q: %s", key, body)
+			}
+		}
+	})
+}
+
+func walkKeys(v interface{}, visit func(string)) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			visit(key)
+			walkKeys(value, visit)
+		}
+	case []interface{}:
+		for _, value := range v {
+			walkKeys(value, visit)
+		}
+	}
+}
+
+func TestBlogResponseHidesAuthorSecrets(t *testing.T) {
+	response := NewBlogResponse(sensitiveBlog())
+	if response.Author == nil || response.Author.DisplayName != "Jane Author" {
+		t.Fatalf("author profile missing: %+v", response.Author)
+	}
+	assertNoSensitiveData(t, response)
+}
+
+func TestBlogResponsesHideAuthorSecrets(t *testing.T) {
+	assertNoSensitiveData(t, NewBlogResponses([]Blog{sensitiveBlog(), sensitiveBlog()}))
+}
+
+func TestBlogSearchResultHidesAuthorSecrets(t *testing.T) {
+	assertNoSensitiveData(t, BlogSearchResult{
+		BlogResponse: NewBlogResponse(sensitiveBlog()),
+		Rank:         0.5,
+		Snippet:      "student <mark>visas</mark>",
+	})
+}
+
+func TestAuthorProfileHidesSecrets(t *testing.T) {
+	assertNoSensitiveData(t, NewAuthorProfile(sensitiveAdmin()))
+}
+
+func TestRevisionResponseHidesAuthorSecrets(t *testing.T) {
+	admin := sensitiveAdmin()
+	rev := BlogRevision{ID: 1, BlogID: 1, Number: 1, Title: "Student visas", Content: "Body", AdminID: &admin.ID, Admin: &admin}
+	assertNoSensitiveData(t, NewBlogRevisionResponse(rev, true))
+}
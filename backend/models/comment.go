@@ -0,0 +1,118 @@
+package models
+
+import "time"
+
+// CommentStatus is where a reader's comment is in moderation. Only
+// approved comments are shown on the site.
+type CommentStatus string
+
+const (
+	CommentStatusPending  CommentStatus = "pending"
+	CommentStatusApproved CommentStatus = "approved"
+	CommentStatusSpam     CommentStatus = "spam"
+)
+
+// IsValid reports whether s is one of the known statuses
+func (s CommentStatus) IsValid() bool {
+	switch s {
+	case CommentStatusPending, CommentStatusApproved, CommentStatusSpam:
+		return true
+	}
+	return false
+}
+
+// Comment is a reader's comment on a blog post. Replies point at the
+// comment they answer through ParentID.
+type Comment struct {
+	ID          uint          `gorm:"primaryKey;autoIncrement" json:"id"`
+	BlogID      uint          `gorm:"not null;index" json:"blog_id"`
+	ParentID    *uint         `gorm:"index" json:"parent_id"`
+	AuthorName  string        `gorm:"size:100;not null" json:"author_name"`
+	AuthorEmail string        `gorm:"size:255;not null" json:"author_email"`
+	Body        string        `gorm:"type:text;not null" json:"body"`
+	Status      CommentStatus `gorm:"size:20;not null;default:'pending';index" json:"status"`
+	IPAddress   string        `gorm:"size:64" json:"ip_address"`
+	UserAgent   string        `gorm:"size:255" json:"user_agent"`
+	ModeratedBy *uint         `json:"moderated_by"`
+	ModeratedAt *time.Time    `json:"moderated_at"`
+	CreatedAt   time.Time     `json:"created_at"`
+	UpdatedAt   time.Time     `json:"updated_at"`
+	Blog        Blog          `gorm:"foreignKey:BlogID;constraint:OnDelete:CASCADE" json:"-"`
+	Parent      *Comment      `gorm:"foreignKey:ParentID;constraint:OnDelete:CASCADE" json:"-"`
+	Moderator   *Admin        `gorm:"foreignKey:ModeratedBy;constraint:OnDelete:SET NULL" json:"-"`
+}
+
+// CommentRequest represents a reader submitting a comment. Website is a
+// honeypot: it is hidden from people, so only bots fill it in.
+type CommentRequest struct {
+	Name     string `json:"name" binding:"required,max=100"`
+	Email    string `json:"email" binding:"required,email,max=255"`
+	Body     string `json:"body" binding:"required,max=5000"`
+	ParentID *uint  `json:"parent_id"`
+	Website  string `json:"website"`
+}
+
+// CommentStatusRequest represents a moderator's decision on a comment
+type CommentStatusRequest struct {
+	Status CommentStatus `json:"status" binding:"required"`
+}
+
+// CommentResponse is the public view of an approved comment, with its
+// approved replies. Email addresses are never shown.
+type CommentResponse struct {
+	ID         uint              `json:"id"`
+	ParentID   *uint             `json:"parent_id"`
+	AuthorName string            `json:"author_name"`
+	Body       string            `json:"body"`
+	CreatedAt  time.Time         `json:"created_at"`
+	Replies    []CommentResponse `json:"replies"`
+}
+
+// CommentModerationResponse is a comment as moderators see it
+type CommentModerationResponse struct {
+	ID          uint          `json:"id"`
+	BlogID      uint          `json:"blog_id"`
+	BlogTitle   string        `json:"blog_title,omitempty"`
+	BlogSlug    string        `json:"blog_slug,omitempty"`
+	ParentID    *uint         `json:"parent_id"`
+	AuthorName  string        `json:"author_name"`
+	AuthorEmail string        `json:"author_email"`
+	Body        string        `json:"body"`
+	Status      CommentStatus `json:"status"`
+	IPAddress   string        `json:"ip_address"`
+	ModeratedBy *uint         `json:"moderated_by"`
+	ModeratedAt *time.Time    `json:"moderated_at"`
+	CreatedAt   time.Time     `json:"created_at"`
+}
+
+// NewCommentResponse builds the public view of comment without replies
+func NewCommentResponse(comment Comment) CommentResponse {
+	return CommentResponse{
+		ID:         comment.ID,
+		ParentID:   comment.ParentID,
+		AuthorName: comment.AuthorName,
+		Body:       comment.Body,
+		CreatedAt:  comment.CreatedAt,
+		Replies:    []CommentResponse{},
+	}
+}
+
+// NewCommentModerationResponse builds the moderators' view of comment.
+// The blog's title and slug are included when it has been preloaded.
+func NewCommentModerationResponse(comment Comment) CommentModerationResponse {
+	return CommentModerationResponse{
+		ID:          comment.ID,
+		BlogID:      comment.BlogID,
+		BlogTitle:   comment.Blog.Title,
+		BlogSlug:    comment.Blog.Slug,
+		ParentID:    comment.ParentID,
+		AuthorName:  comment.AuthorName,
+		AuthorEmail: comment.AuthorEmail,
+		Body:        comment.Body,
+		Status:      comment.Status,
+		IPAddress:   comment.IPAddress,
+		ModeratedBy: comment.ModeratedBy,
+		ModeratedAt: comment.ModeratedAt,
+		CreatedAt:   comment.CreatedAt,
+	}
+}
@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// AdminInvite is a single-use invitation for a new admin account.
+// Only the SHA-256 hash of the token is stored.
+type AdminInvite struct {
+	ID          uint       `gorm:"primaryKey;autoIncrement" json:"id"`
+	Email       string     `gorm:"size:100;not null;index" json:"email"`
+	Role        Role       `gorm:"size:20;not null" json:"role"`
+	TokenHash   string     `gorm:"size:64;not null;uniqueIndex" json:"-"`
+	InvitedByID uint       `gorm:"not null" json:"invited_by_id"`
+	ExpiresAt   time.Time  `gorm:"not null" json:"expires_at"`
+	AcceptedAt  *time.Time `json:"accepted_at"`
+	RevokedAt   *time.Time `json:"revoked_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// IsPending reports whether the invite can still be redeemed
+func (i *AdminInvite) IsPending(now time.Time) bool {
+	return i.AcceptedAt == nil && i.RevokedAt == nil && now.Before(i.ExpiresAt)
+}
+
+// CreateInviteRequest represents the body of an invite creation call
+type CreateInviteRequest struct {
+	Email string `json:"email" binding:"required,email"`
+	Role  Role   `json:"role" binding:"required"`
+}
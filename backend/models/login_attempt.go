@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// LoginAttempt holds the failure counter for one throttling key, such
+// as "ip:203.0.113.7" or "email:someone@example.com"
+type LoginAttempt struct {
+	Key           string    `gorm:"primaryKey;size:255" json:"key"`
+	Failures      int       `gorm:"not null;default:0" json:"failures"`
+	LastFailureAt time.Time `json:"last_failure_at"`
+	BlockedUntil  time.Time `json:"blocked_until"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// LoginLockout records every time a key crossed the lockout threshold
+type LoginLockout struct {
+	ID          uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	Key         string    `gorm:"size:255;not null;index" json:"key"`
+	Email       string    `gorm:"size:100;index" json:"email"`
+	IPAddress   string    `gorm:"size:64" json:"ip_address"`
+	UserAgent   string    `gorm:"size:255" json:"user_agent"`
+	Failures    int       `json:"failures"`
+	LockedUntil time.Time `json:"locked_until"`
+	CreatedAt   time.Time `json:"created_at"`
+}
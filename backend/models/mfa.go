@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// RecoveryCode is a hashed single-use code that can stand in for a TOTP
+// code when the admin loses their authenticator
+type RecoveryCode struct {
+	ID        uint       `gorm:"primaryKey;autoIncrement" json:"id"`
+	AdminID   uint       `gorm:"not null;index" json:"admin_id"`
+	CodeHash  string     `gorm:"size:64;not null;uniqueIndex" json:"-"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// MFALoginRequest is the second login step for accounts with TOTP enabled.
+// Either Code or RecoveryCode must be given.
+type MFALoginRequest struct {
+	MFAToken     string `json:"mfa_token" binding:"required"`
+	Code         string `json:"code"`
+	RecoveryCode string `json:"recovery_code"`
+}
+
+// TOTPCodeRequest carries a code from the authenticator app
+type TOTPCodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// DisableTOTPRequest turns two-factor authentication off
+type DisableTOTPRequest struct {
+	Password string `json:"password" binding:"required"`
+	Code     string `json:"code" binding:"required"`
+}
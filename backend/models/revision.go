@@ -0,0 +1,56 @@
+package models
+
+import "time"
+
+// BlogRevision is a snapshot of a blog as it was saved at one point in
+// time. Number counts up from 1 for each blog.
+type BlogRevision struct {
+	ID      uint   `gorm:"primaryKey;autoIncrement" json:"id"`
+	BlogID  uint   `gorm:"not null;uniqueIndex:idx_blog_revisions_number" json:"blog_id"`
+	Number  int    `gorm:"not null;uniqueIndex:idx_blog_revisions_number" json:"number"`
+	Title   string `gorm:"size:255;not null" json:"title"`
+	Content string `gorm:"type:text;not null" json:"content"`
+	// Format is the content format; revisions that predate it are HTML
+	Format    string    `gorm:"size:20;not null;default:'html'" json:"format"`
+	Image     *string   `json:"image"`
+	AdminID   *uint     `gorm:"index" json:"admin_id"`
+	CreatedAt time.Time `json:"created_at"`
+	Admin     *Admin    `gorm:"foreignKey:AdminID;constraint:OnDelete:SET NULL" json:"-"`
+}
+
+// BlogRevisionResponse is the API representation of a revision. Content
+// is left out of listings.
+type BlogRevisionResponse struct {
+	ID        uint           `json:"id"`
+	BlogID    uint           `json:"blog_id"`
+	Number    int            `json:"number"`
+	Title     string         `json:"title"`
+	Content   string         `json:"content,omitempty"`
+	Format    string         `json:"format"`
+	Image     *string        `json:"image"`
+	Author    *AuthorProfile `json:"author,omitempty"`
+	CreatedAt time.Time      `json:"created_at"`
+}
+
+// NewBlogRevisionResponse builds the API representation of rev
+func NewBlogRevisionResponse(rev BlogRevision, withContent bool) BlogRevisionResponse {
+	response := BlogRevisionResponse{
+		ID:        rev.ID,
+		BlogID:    rev.BlogID,
+		Number:    rev.Number,
+		Title:     rev.Title,
+		Format:    rev.Format,
+		CreatedAt: rev.CreatedAt,
+	}
+	if withContent {
+		response.Content = rev.Content
+	}
+	if rev.Admin != nil {
+		response.Author = NewAuthorProfile(*rev.Admin)
+	}
+	if rev.Image != nil && *rev.Image != "" {
+		imagePath := "/uploads/" + *rev.Image
+		response.Image = &imagePath
+	}
+	return response
+}
@@ -0,0 +1,86 @@
+package models
+
+// Role is the access level granted to an admin account
+type Role string
+
+const (
+	RoleOwner  Role = "owner"
+	RoleEditor Role = "editor"
+	RoleAuthor Role = "author"
+	RoleViewer Role = "viewer"
+)
+
+// Permissions checked by middleware.RequirePermission and the handlers.
+// The ":own" variants only apply to records the admin created.
+const (
+	PermBlogRead      = "blog:read"
+	PermBlogCreate    = "blog:create"
+	PermBlogUpdateOwn = "blog:update:own"
+	PermBlogUpdateAny = "blog:update"
+	PermBlogDeleteOwn = "blog:delete:own"
+	PermBlogDeleteAny = "blog:delete"
+	PermBlogPublish   = "blog:publish"
+	PermTaxonomy      = "taxonomy:manage"
+	PermComments      = "comment:moderate"
+	PermAdminManage   = "admin:manage"
+	PermAdminInvite   = "admin:invite"
+	PermAuditRead     = "audit:read"
+)
+
+var rolePermissions = map[Role][]string{
+	RoleOwner: {
+		PermBlogRead, PermBlogCreate,
+		PermBlogUpdateOwn, PermBlogUpdateAny,
+		PermBlogDeleteOwn, PermBlogDeleteAny,
+		PermBlogPublish, PermTaxonomy,
+		PermComments,
+		PermAdminManage, PermAdminInvite,
+		PermAuditRead,
+	},
+	RoleEditor: {
+		PermBlogRead, PermBlogCreate,
+		PermBlogUpdateOwn, PermBlogUpdateAny,
+		PermBlogDeleteOwn, PermBlogDeleteAny,
+		PermBlogPublish, PermTaxonomy,
+		PermComments,
+	},
+	RoleAuthor: {
+		PermBlogRead, PermBlogCreate,
+		PermBlogUpdateOwn, PermBlogDeleteOwn,
+	},
+	RoleViewer: {
+		PermBlogRead,
+	},
+}
+
+// IsValid reports whether r is one of the known roles
+func (r Role) IsValid() bool {
+	_, ok := rolePermissions[r]
+	return ok
+}
+
+// Permissions returns the permissions granted to the role
+func (r Role) Permissions() []string {
+	return rolePermissions[r]
+}
+
+// Can reports whether the role grants the given permission
+func (r Role) Can(permission string) bool {
+	for _, p := range rolePermissions[r] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// RolesWith returns the roles that grant the given permission
+func RolesWith(permission string) []Role {
+	var roles []Role
+	for _, role := range []Role{RoleOwner, RoleEditor, RoleAuthor, RoleViewer} {
+		if role.Can(permission) {
+			roles = append(roles, role)
+		}
+	}
+	return roles
+}
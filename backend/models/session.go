@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// AdminSession tracks a refresh token issued to an admin at login.
+// Access tokens carry the session ID so revoking the session
+// invalidates them immediately.
+type AdminSession struct {
+	ID                uint       `gorm:"primaryKey;autoIncrement" json:"id"`
+	AdminID           uint       `gorm:"not null;index" json:"admin_id"`
+	RefreshTokenHash  string     `gorm:"size:64;not null;uniqueIndex" json:"-"`
+	PreviousTokenHash string     `gorm:"size:64;index" json:"-"`
+	UserAgent         string     `gorm:"size:255" json:"user_agent"`
+	IPAddress         string     `gorm:"size:64" json:"ip_address"`
+	ExpiresAt         time.Time  `gorm:"not null" json:"expires_at"`
+	LastUsedAt        time.Time  `json:"last_used_at"`
+	RevokedAt         *time.Time `json:"revoked_at"`
+	CreatedAt         time.Time  `json:"created_at"`
+}
+
+// IsActive reports whether the session can still be used
+func (s *AdminSession) IsActive(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
+
+// RefreshRequest represents the body of a token refresh call
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
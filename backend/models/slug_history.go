@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// BlogSlugHistory remembers slugs a blog used to have, so links to an
+// old slug can be redirected to the current one
+type BlogSlugHistory struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	BlogID    uint      `gorm:"not null;index" json:"blog_id"`
+	Slug      string    `gorm:"size:255;not null;uniqueIndex" json:"slug"`
+	CreatedAt time.Time `json:"created_at"`
+}
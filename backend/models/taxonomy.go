@@ -0,0 +1,75 @@
+package models
+
+import "time"
+
+// Tag is a free-form label on blog posts
+type Tag struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name      string    `gorm:"size:100;not null" json:"name"`
+	Slug      string    `gorm:"size:120;not null;uniqueIndex" json:"slug"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Category is a node in the hierarchy blog posts are filed under
+type Category struct {
+	ID          uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name        string    `gorm:"size:100;not null" json:"name"`
+	Slug        string    `gorm:"size:120;not null;uniqueIndex" json:"slug"`
+	Description string    `gorm:"type:text" json:"description"`
+	ParentID    *uint     `gorm:"index" json:"parent_id"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	Parent      *Category `gorm:"foreignKey:ParentID" json:"-"`
+}
+
+// TagRequest represents the body of a tag create or update call
+type TagRequest struct {
+	Name string `json:"name" binding:"required,max=100"`
+	Slug string `json:"slug" binding:"max=120"`
+}
+
+// CategoryRequest represents the body of a category create or update call
+type CategoryRequest struct {
+	Name        string `json:"name" binding:"required,max=100"`
+	Slug        string `json:"slug" binding:"max=120"`
+	Description string `json:"description" binding:"max=2000"`
+	ParentID    *uint  `json:"parent_id"`
+}
+
+// TagResponse is the API representation of a tag. PostCount is only
+// filled in on taxonomy listings.
+type TagResponse struct {
+	ID        uint   `json:"id"`
+	Name      string `json:"name"`
+	Slug      string `json:"slug"`
+	PostCount *int64 `json:"post_count,omitempty"`
+}
+
+// CategoryResponse is the API representation of a category. Listings
+// nest children under their parent.
+type CategoryResponse struct {
+	ID          uint               `json:"id"`
+	Name        string             `json:"name"`
+	Slug        string             `json:"slug"`
+	Description string             `json:"description,omitempty"`
+	ParentID    *uint              `json:"parent_id"`
+	PostCount   *int64             `json:"post_count,omitempty"`
+	Children    []CategoryResponse `json:"children,omitempty"`
+}
+
+// NewTagResponse builds the API representation of a tag
+func NewTagResponse(tag Tag) TagResponse {
+	return TagResponse{ID: tag.ID, Name: tag.Name, Slug: tag.Slug}
+}
+
+// NewCategoryResponse builds the API representation of a category
+func NewCategoryResponse(category Category) CategoryResponse {
+	return CategoryResponse{
+		ID:          category.ID,
+		Name:        category.Name,
+		Slug:        category.Slug,
+		Description: category.Description,
+		ParentID:    category.ParentID,
+	}
+}
@@ -0,0 +1,89 @@
+package ogcard
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+const defaultCacheDir = "./cache/og"
+
+// CacheDir is where rendered cards are kept, from OG_CACHE_DIR
+func CacheDir() string {
+	if dir := os.Getenv("OG_CACHE_DIR"); dir != "" {
+		return dir
+	}
+	return defaultCacheDir
+}
+
+// Key identifies a card by everything drawn on it, so a card is redrawn
+// whenever the title, the image or the layout changes
+func Key(title, image string) string {
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s\x00%s\x00%s\x00%s", layoutVersion, os.Getenv("OG_LOGO_FILE"), title, image)
+	return hex.EncodeToString(hash.Sum(nil))[:32]
+}
+
+// Cached returns the path of the blog's card for key, drawing it with
+// the result of card when it isn't on disk yet. Cards the blog had under
+// other keys are removed once the new one is written.
+func Cached(blogID uint, key string, card func() (Card, error)) (string, error) {
+	dir := CacheDir()
+	path := filepath.Join(dir, fmt.Sprintf("%d-%s.png", blogID, key))
+	if _, err := os.Stat(path); err == nil {
+		return path, nil
+	}
+
+	c, err := card()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+
+	// Write beside the final name and rename, so concurrent requests
+	// never serve a half-written file
+	tmp, err := os.CreateTemp(dir, fmt.Sprintf("%d-*.tmp", blogID))
+	if err != nil {
+		return "", err
+	}
+	if err := tmp.Chmod(0644); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return "", err
+	}
+	if err := Render(tmp, c); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return "", err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return "", err
+	}
+
+	removeCards(blogID, path)
+	return path, nil
+}
+
+// Invalidate removes every cached card of the blog
+func Invalidate(blogID uint) {
+	removeCards(blogID, "")
+}
+
+// removeCards deletes the blog's cached cards other than keep
+func removeCards(blogID uint, keep string) {
+	matches, _ := filepath.Glob(filepath.Join(CacheDir(), fmt.Sprintf("%d-*.png", blogID)))
+	for _, match := range matches {
+		if match != keep {
+			os.Remove(match)
+		}
+	}
+}
@@ -0,0 +1,255 @@
+// Package ogcard draws the Open Graph preview cards shown when a blog
+// post is shared: the post's image behind its title and the site logo.
+package ogcard
+
+import (
+	"bytes"
+	_ "embed"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"os"
+	"strings"
+
+	// Post images may be any of the upload formats
+	_ "image/gif"
+	_ "image/jpeg"
+
+	"golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+// Width and Height are the card size social networks expect
+const (
+	Width  = 1200
+	Height = 630
+)
+
+const (
+	margin     = 72
+	logoHeight = 96
+	maxLines   = 3
+)
+
+// layoutVersion is part of every cache key; bump it when the drawing
+// changes so cached cards are redrawn
+const layoutVersion = "1"
+
+var (
+	//go:embed logo.png
+	defaultLogo []byte
+
+	// brand is the background used when a post has no usable image
+	brand = color.RGBA{0x16, 0xa3, 0x4a, 0xff}
+
+	titleFont = mustParseFont(gobold.TTF)
+)
+
+// Card is what a preview shows. Image and Logo may be nil.
+type Card struct {
+	Title string
+	Image image.Image
+	Logo  image.Image
+}
+
+// Render draws card as a PNG
+func Render(w io.Writer, card Card) error {
+	canvas := image.NewRGBA(image.Rect(0, 0, Width, Height))
+
+	if card.Image != nil {
+		drawCover(canvas, card.Image)
+	} else {
+		draw.Draw(canvas, canvas.Bounds(), image.NewUniform(brand), image.Point{}, draw.Src)
+	}
+	shadeBottom(canvas)
+
+	if card.Logo != nil {
+		drawLogo(canvas, card.Logo)
+	}
+	if err := drawTitle(canvas, card.Title); err != nil {
+		return err
+	}
+
+	return png.Encode(w, canvas)
+}
+
+// LoadLogo returns the logo to put on cards: the image at OG_LOGO_FILE
+// when set, otherwise the site logo built into the binary
+func LoadLogo() (image.Image, error) {
+	if path := os.Getenv("OG_LOGO_FILE"); path != "" {
+		return LoadImage(path)
+	}
+	logo, _, err := image.Decode(bytes.NewReader(defaultLogo))
+	return logo, err
+}
+
+// LoadImage decodes the image file at path
+func LoadImage(path string) (image.Image, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	img, _, err := image.Decode(file)
+	return img, err
+}
+
+// drawCover scales src to fill dst, cropping whatever overflows equally
+// from both sides
+func drawCover(dst *image.RGBA, src image.Image) {
+	bounds := src.Bounds()
+	sw, sh := bounds.Dx(), bounds.Dy()
+	if sw == 0 || sh == 0 {
+		return
+	}
+
+	crop := bounds
+	if sw*Height > sh*Width {
+		w := sh * Width / Height
+		crop.Min.X += (sw - w) / 2
+		crop.Max.X = crop.Min.X + w
+	} else {
+		h := sw * Height / Width
+		crop.Min.Y += (sh - h) / 2
+		crop.Max.Y = crop.Min.Y + h
+	}
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, crop, draw.Src, nil)
+}
+
+// shadeBottom darkens the lower part of the card so white text stays
+// readable on any photo
+func shadeBottom(dst *image.RGBA) {
+	start := Height / 3
+	for y := start; y < Height; y++ {
+		alpha := uint8(200 * (y - start) / (Height - start))
+		shade := image.NewUniform(color.NRGBA{A: alpha})
+		draw.Draw(dst, image.Rect(0, y, Width, y+1), shade, image.Point{}, draw.Over)
+	}
+}
+
+func drawLogo(dst *image.RGBA, logo image.Image) {
+	bounds := logo.Bounds()
+	if bounds.Dx() == 0 || bounds.Dy() == 0 {
+		return
+	}
+	w := bounds.Dx() * logoHeight / bounds.Dy()
+	target := image.Rect(margin, margin, margin+w, margin+logoHeight)
+	draw.CatmullRom.Scale(dst, target, logo, bounds, draw.Over, nil)
+}
+
+// drawTitle writes the title over the bottom of the card, shrinking the
+// type for long titles and cutting what still doesn't fit
+func drawTitle(dst *image.RGBA, title string) error {
+	title = strings.Join(strings.Fields(title), " ")
+	if title == "" {
+		return nil
+	}
+
+	var (
+		face  font.Face
+		lines []string
+		size  float64
+	)
+	for _, size = range []float64{72, 60, 50} {
+		var err error
+		face, err = opentype.NewFace(titleFont, &opentype.FaceOptions{Size: size, DPI: 72, Hinting: font.HintingFull})
+		if err != nil {
+			return err
+		}
+		lines = wrap(face, title, Width-2*margin)
+		if len(lines) <= maxLines {
+			break
+		}
+	}
+	if len(lines) > maxLines {
+		lines = lines[:maxLines]
+		lines[maxLines-1] = truncate(face, lines[maxLines-1]+" …", Width-2*margin)
+	}
+
+	lineHeight := int(size * 1.2)
+	drawer := &font.Drawer{Dst: dst, Src: image.White, Face: face}
+	y := Height - margin - lineHeight*(len(lines)-1)
+	for _, line := range lines {
+		drawer.Dot = fixed.P(margin, y)
+		drawer.DrawString(line)
+		y += lineHeight
+	}
+	return nil
+}
+
+// wrap breaks text into lines no wider than width, splitting words
+// only when a single word is wider than a line
+func wrap(face font.Face, text string, width int) []string {
+	limit := fixed.I(width)
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(text) {
+		candidate := word
+		if line != "" {
+			candidate = line + " " + word
+		}
+		if font.MeasureString(face, candidate) <= limit {
+			line = candidate
+			continue
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+		line = word
+		for font.MeasureString(face, line) > limit {
+			cut := truncateRunes(face, line, limit)
+			lines = append(lines, line[:cut])
+			line = line[cut:]
+		}
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// truncate drops words from the end of line until it fits, ending it
+// with an ellipsis
+func truncate(face font.Face, line string, width int) string {
+	limit := fixed.I(width)
+	for font.MeasureString(face, line) > limit {
+		trimmed := strings.TrimSuffix(line, " …")
+		if i := strings.LastIndex(trimmed, " "); i > 0 {
+			line = trimmed[:i] + " …"
+			continue
+		}
+		runes := []rune(trimmed)
+		if len(runes) <= 1 {
+			return "…"
+		}
+		line = string(runes[:len(runes)-1]) + " …"
+	}
+	return line
+}
+
+// truncateRunes returns the byte length of the longest prefix of s that
+// fits within limit, and at least one rune
+func truncateRunes(face font.Face, s string, limit fixed.Int26_6) int {
+	cut := 0
+	for i, r := range s {
+		next := i + len(string(r))
+		if cut > 0 && font.MeasureString(face, s[:next]) > limit {
+			break
+		}
+		cut = next
+	}
+	return cut
+}
+
+func mustParseFont(data []byte) *opentype.Font {
+	f, err := opentype.Parse(data)
+	if err != nil {
+		panic("ogcard: " + err.Error())
+	}
+	return f
+}
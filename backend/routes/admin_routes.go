@@ -3,6 +3,7 @@ package routes
 import (
 	"backend/controllers"
 	"backend/middleware"
+	"backend/models"
 
 	"github.com/gin-gonic/gin"
 )
@@ -12,24 +13,108 @@ func AdminRoutes(r *gin.RouterGroup) {
 	public := r.Group("/admin")
 	{
 		public.POST("/signup", controllers.AdminSignup)
+		public.POST("/bootstrap", controllers.AdminBootstrap)
 		public.POST("/login", controllers.AdminLogin)
+		public.POST("/login/mfa", controllers.AdminLoginMFA)
+		public.POST("/refresh", controllers.RefreshSession)
+		public.POST("/password/forgot", controllers.ForgotPassword)
+		public.POST("/password/reset", controllers.ResetPassword)
 
-		// Blog viewing routes (public)
+		// Blog viewing routes (public, published posts only)
 		public.GET("/blogs", controllers.GetBlogs)
 		public.GET("/blogs/:id", controllers.GetBlog)
+
+		// Taxonomy listings and archives (public)
+		public.GET("/tags", controllers.GetTags)
+		public.GET("/tags/:slug", controllers.GetTagArchive)
+		public.GET("/categories", controllers.GetCategories)
+		public.GET("/categories/:slug", controllers.GetCategoryArchive)
 	}
 
-	// Protected admin routes (require authentication)
+	// Protected admin routes (require a session token or an API key)
 	protected := r.Group("/admin")
 	protected.Use(middleware.RequireAuth)
+	{
+		// Blog management routes; ownership of the individual post is
+		// checked in the handlers against the ":own"/"any" permissions
+		protected.POST("/blogs", middleware.RequirePermission(models.PermBlogCreate), controllers.CreateBlog)
+		protected.PUT("/blogs/:id", middleware.RequirePermission(models.PermBlogUpdateOwn), controllers.UpdateBlog)
+		protected.DELETE("/blogs/:id", middleware.RequirePermission(models.PermBlogDeleteOwn), controllers.DeleteBlog)
+		protected.POST("/blogs/:id/status", middleware.RequirePermission(models.PermBlogUpdateOwn), controllers.UpdateBlogStatus)
+		protected.PUT("/blogs/:id/schedule", middleware.RequirePermission(models.PermBlogPublish), controllers.ScheduleBlog)
+		protected.DELETE("/blogs/:id/schedule", middleware.RequirePermission(models.PermBlogPublish), controllers.UnscheduleBlog)
+		protected.POST("/blogs/:id/revisions/:rev/restore", middleware.RequirePermission(models.PermBlogUpdateOwn), controllers.RestoreBlogRevision)
+
+		// Taxonomy management routes
+		protected.POST("/tags", middleware.RequirePermission(models.PermTaxonomy), controllers.CreateTag)
+		protected.PUT("/tags/:id", middleware.RequirePermission(models.PermTaxonomy), controllers.UpdateTag)
+		protected.DELETE("/tags/:id", middleware.RequirePermission(models.PermTaxonomy), controllers.DeleteTag)
+		protected.POST("/categories", middleware.RequirePermission(models.PermTaxonomy), controllers.CreateCategory)
+		protected.PUT("/categories/:id", middleware.RequirePermission(models.PermTaxonomy), controllers.UpdateCategory)
+		protected.DELETE("/categories/:id", middleware.RequirePermission(models.PermTaxonomy), controllers.DeleteCategory)
+
+		// Comment moderation routes
+		protected.GET("/manage/comments", middleware.RequirePermission(models.PermComments), controllers.GetComments)
+		protected.POST("/comments/:id/status", middleware.RequirePermission(models.PermComments), controllers.UpdateCommentStatus)
+		protected.DELETE("/comments/:id", middleware.RequirePermission(models.PermComments), controllers.DeleteComment)
+
+		// Blog views including drafts, for the dashboard
+		protected.GET("/manage/blogs", middleware.RequirePermission(models.PermBlogRead), controllers.GetAdminBlogs)
+		protected.GET("/manage/blogs/:id", middleware.RequirePermission(models.PermBlogRead), controllers.GetAdminBlog)
+		protected.GET("/manage/blogs/:id/revisions", middleware.RequirePermission(models.PermBlogRead), controllers.GetBlogRevisions)
+		protected.GET("/manage/blogs/:id/revisions/diff", middleware.RequirePermission(models.PermBlogRead), controllers.DiffBlogRevisions)
+		protected.GET("/manage/blogs/:id/revisions/:rev", middleware.RequirePermission(models.PermBlogRead), controllers.GetBlogRevision)
+	}
+
+	// Account routes for admins signed in through the dashboard; API keys
+	// are rejected here so a leaked key can't escalate
+	session := r.Group("/admin")
+	session.Use(middleware.RequireAuth, middleware.RequireSession)
 	{
 		// Dashboard route
-		protected.GET("/dashboard", adminDashboard)
+		session.GET("/dashboard", adminDashboard)
+
+		// Session management routes
+		session.GET("/verify-session", controllers.VerifySession)
+		session.POST("/logout", controllers.AdminLogout)
+		session.POST("/logout-all", controllers.AdminLogoutAll)
+		session.POST("/password/change", controllers.ChangePassword)
+
+		// Public author profile
+		session.GET("/profile", controllers.GetProfile)
+		session.PUT("/profile", controllers.UpdateProfile)
+
+		// Two-factor authentication routes
+		session.POST("/mfa/totp/setup", controllers.SetupTOTP)
+		session.POST("/mfa/totp/confirm", controllers.ConfirmTOTP)
+		session.POST("/mfa/totp/disable", controllers.DisableTOTP)
+		session.POST("/mfa/recovery-codes", controllers.RegenerateRecoveryCodes)
+
+		// API key management routes
+		session.POST("/api-keys", controllers.CreateAPIKey)
+		session.GET("/api-keys", controllers.ListAPIKeys)
+		session.DELETE("/api-keys/:id", controllers.RevokeAPIKey)
+
+		// Invite management routes
+		session.POST("/invites", middleware.RequirePermission(models.PermAdminInvite), controllers.CreateInvite)
+		session.GET("/invites", middleware.RequirePermission(models.PermAdminInvite), controllers.GetInvites)
+		session.DELETE("/invites/:id", middleware.RequirePermission(models.PermAdminInvite), controllers.RevokeInvite)
+
+		// Admin user management routes (owners only)
+		users := session.Group("/users")
+		users.Use(middleware.RequirePermission(models.PermAdminManage))
+		{
+			users.GET("", controllers.ListAdmins)
+			users.GET("/:id", controllers.GetAdmin)
+			users.PUT("/:id", controllers.UpdateAdmin)
+			users.POST("/:id/disable", controllers.DisableAdmin)
+			users.POST("/:id/enable", controllers.EnableAdmin)
+			users.POST("/:id/reset-password", controllers.ResetAdminPassword)
+			users.DELETE("/:id", controllers.DeleteAdmin)
+		}
 
-		// Blog management routes
-		protected.POST("/blogs", controllers.CreateBlog)
-		protected.PUT("/blogs/:id", controllers.UpdateBlog)
-		protected.DELETE("/blogs/:id", controllers.DeleteBlog)
+		// Audit log
+		session.GET("/audit", middleware.RequirePermission(models.PermAuditRead), controllers.GetAuditEvents)
 	}
 }
 
@@ -56,8 +141,10 @@ func adminDashboard(c *gin.Context) {
 	c.JSON(200, gin.H{
 		"message": "Welcome to admin dashboard",
 		"adminID": adminID,
+		"role":    c.MustGet("adminRole"),
 		"links": []gin.H{
-			{"description": "Manage blogs", "path": "/api/admin/blogs"},
+			{"description": "Manage blogs", "path": "/api/admin/manage/blogs"},
+			{"description": "Moderate comments", "path": "/api/admin/manage/comments"},
 			{"description": "Manage users", "path": "/api/admin/users"},
 		},
 	})
@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"backend/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BlogRoutes registers the public blog endpoints that sit outside the
+// admin API
+func BlogRoutes(r *gin.RouterGroup) {
+	blogs := r.Group("/blogs")
+	{
+		blogs.GET("/search", controllers.SearchBlogs)
+		blogs.GET("/:id/og.png", controllers.GetBlogCard)
+		blogs.GET("/:id/comments", controllers.GetBlogComments)
+		blogs.POST("/:id/comments", controllers.CreateComment)
+	}
+}
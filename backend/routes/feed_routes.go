@@ -0,0 +1,16 @@
+package routes
+
+import (
+	"backend/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FeedRoutes registers the RSS, Atom and JSON feeds of published posts
+func FeedRoutes(r *gin.Engine) {
+	feeds := r.Group("/feeds")
+	{
+		feeds.GET("/:file", controllers.GetBlogFeed)
+		feeds.GET("/category/:file", controllers.GetCategoryFeed)
+	}
+}
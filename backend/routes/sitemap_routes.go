@@ -0,0 +1,14 @@
+package routes
+
+import (
+	"backend/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SitemapRoutes registers /sitemap.xml, its parts and /robots.txt
+func SitemapRoutes(r *gin.Engine) {
+	r.GET("/sitemap.xml", controllers.GetSitemap)
+	r.GET("/sitemaps/:file", controllers.GetSitemapChunk)
+	r.GET("/robots.txt", controllers.GetRobots)
+}
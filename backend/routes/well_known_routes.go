@@ -0,0 +1,16 @@
+package routes
+
+import (
+	"backend/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WellKnownRoutes registers the /.well-known endpoints other services
+// use to discover how to talk to this API
+func WellKnownRoutes(r *gin.Engine) {
+	wellKnown := r.Group("/.well-known")
+	{
+		wellKnown.GET("/jwks.json", controllers.JWKS)
+	}
+}
@@ -0,0 +1,66 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"backend/audit"
+	"backend/database"
+	"backend/models"
+
+	"gorm.io/gorm"
+)
+
+// PublishDueBlogs publishes drafts and posts in review whose publish_at
+// has passed. A transaction-scoped advisory lock means only one replica
+// does the work per tick; the others return straight away.
+func PublishDueBlogs(ctx context.Context) error {
+	return database.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var locked bool
+		if err := tx.Raw("SELECT pg_try_advisory_xact_lock(hashtext('publish_scheduled_blogs'))").Scan(&locked).Error; err != nil {
+			return err
+		}
+		if !locked {
+			return nil
+		}
+
+		now := time.Now()
+		var due []models.Blog
+		if err := tx.
+			Where("publish_at <= ? AND status IN ?", now, []models.BlogStatus{models.BlogStatusDraft, models.BlogStatusReview}).
+			Find(&due).Error; err != nil {
+			return err
+		}
+
+		for _, blog := range due {
+			publishedAt := now
+			if blog.PublishedAt != nil {
+				publishedAt = *blog.PublishedAt
+			}
+			if err := tx.Model(&blog).Updates(map[string]interface{}{
+				"status":       models.BlogStatusPublished,
+				"published_at": publishedAt,
+				"publish_at":   nil,
+				"updated_at":   now,
+			}).Error; err != nil {
+				return err
+			}
+
+			if err := audit.RecordTx(tx, nil, audit.Entry{
+				Action:     audit.ActionBlogPublishScheduled,
+				TargetType: "blog",
+				TargetID:   blog.ID,
+				Before:     map[string]interface{}{"status": blog.Status},
+				After:      map[string]interface{}{"status": models.BlogStatusPublished},
+			}); err != nil {
+				return err
+			}
+		}
+
+		if len(due) > 0 {
+			log.Printf("Scheduler: published %d scheduled blog(s)", len(due))
+		}
+		return nil
+	})
+}
@@ -0,0 +1,67 @@
+// Package sitemap writes documents in the sitemaps.org protocol
+package sitemap
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+// MaxURLs is the most URLs one sitemap may list; larger sites are split
+// into several sitemaps listed by an index
+const MaxURLs = 50000
+
+const namespace = "http://www.sitemaps.org/schemas/sitemap/0.9"
+
+// URL is one page in a sitemap. Loc must be absolute; a zero LastMod is
+// left out.
+type URL struct {
+	Loc     string
+	LastMod time.Time
+}
+
+type urlSet struct {
+	XMLName xml.Name   `xml:"urlset"`
+	XMLNS   string     `xml:"xmlns,attr"`
+	URLs    []location `xml:"url"`
+}
+
+type sitemapIndex struct {
+	XMLName  xml.Name   `xml:"sitemapindex"`
+	XMLNS    string     `xml:"xmlns,attr"`
+	Sitemaps []location `xml:"sitemap"`
+}
+
+type location struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+// Write returns a sitemap listing urls
+func Write(urls []URL) ([]byte, error) {
+	return marshal(urlSet{XMLNS: namespace, URLs: locations(urls)})
+}
+
+// WriteIndex returns a sitemap index pointing at the sitemaps in urls
+func WriteIndex(sitemaps []URL) ([]byte, error) {
+	return marshal(sitemapIndex{XMLNS: namespace, Sitemaps: locations(sitemaps)})
+}
+
+func locations(urls []URL) []location {
+	result := make([]location, 0, len(urls))
+	for _, u := range urls {
+		loc := location{Loc: u.Loc}
+		if !u.LastMod.IsZero() {
+			loc.LastMod = u.LastMod.UTC().Format(time.RFC3339)
+		}
+		result = append(result, loc)
+	}
+	return result
+}
+
+func marshal(v interface{}) ([]byte, error) {
+	body, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), body...), nil
+}
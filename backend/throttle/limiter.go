@@ -0,0 +1,144 @@
+package throttle
+
+import (
+	"context"
+	"time"
+)
+
+// Policy describes how failures on a key are penalised. The first
+// FreeAttempts failures cost nothing, each one after that doubles the
+// wait starting at BaseDelay, and reaching LockoutThreshold locks the
+// key for LockoutDuration. Counters reset after Window without failures.
+type Policy struct {
+	FreeAttempts     int
+	BaseDelay        time.Duration
+	MaxDelay         time.Duration
+	LockoutThreshold int
+	LockoutDuration  time.Duration
+	Window           time.Duration
+}
+
+// Limiter applies a Policy to keys in the configured store
+type Limiter struct {
+	Policy Policy
+}
+
+// Result is the outcome of recording a failure
+type Result struct {
+	Failures   int
+	RetryAfter time.Duration
+	// LockedOut is true only for the failure that triggered the lockout
+	LockedOut bool
+	// Blocked is true when Attempt found the key waiting and counted nothing
+	Blocked bool
+}
+
+func (r Result) with(entry Entry, now time.Time) Result {
+	r.Failures = entry.Failures
+	if wait := entry.BlockedUntil.Sub(now); wait > 0 {
+		r.RetryAfter = wait
+	}
+	return r
+}
+
+// Check returns how long the key must wait before its next attempt
+func (l *Limiter) Check(ctx context.Context, key string) (time.Duration, error) {
+	entry, err := store.Get(ctx, key)
+	if err != nil {
+		return 0, err
+	}
+	if wait := time.Until(entry.BlockedUntil); wait > 0 {
+		return wait, nil
+	}
+	return 0, nil
+}
+
+// Fail records a failed attempt for the key
+func (l *Limiter) Fail(ctx context.Context, key string) (Result, error) {
+	now := time.Now()
+	var result Result
+
+	entry, err := store.Update(ctx, key, func(e *Entry) {
+		result.LockedOut = l.Policy.fail(e, now)
+	})
+	if err != nil {
+		return Result{}, err
+	}
+	return result.with(entry, now), nil
+}
+
+// Attempt checks the key and, unless it is blocked, counts an attempt
+// against it in one atomic update, so concurrent requests can't all pass
+// the check before any of them is recorded. The attempt stands as a
+// failure until Forgive or Reset takes it back.
+func (l *Limiter) Attempt(ctx context.Context, key string) (Result, error) {
+	now := time.Now()
+	var result Result
+
+	entry, err := store.Update(ctx, key, func(e *Entry) {
+		if e.BlockedUntil.After(now) {
+			result.Blocked = true
+			return
+		}
+		result.LockedOut = l.Policy.fail(e, now)
+	})
+	if err != nil {
+		return Result{}, err
+	}
+	return result.with(entry, now), nil
+}
+
+// Forgive takes back one attempt counted by Attempt that succeeded, along
+// with any delay it imposed
+func (l *Limiter) Forgive(ctx context.Context, key string) error {
+	_, err := store.Update(ctx, key, func(e *Entry) {
+		if e.Failures == 0 {
+			return
+		}
+		e.Failures--
+		if e.Failures < l.Policy.LockoutThreshold {
+			e.BlockedUntil = e.LastFailureAt.Add(l.Policy.delay(e.Failures))
+		}
+	})
+	return err
+}
+
+// Reset clears the key after a successful attempt
+func (l *Limiter) Reset(ctx context.Context, key string) error {
+	return store.Delete(ctx, key)
+}
+
+// fail counts a failure on e and reports whether it triggered a lockout
+func (p Policy) fail(e *Entry, now time.Time) bool {
+	if now.Sub(e.LastFailureAt) > p.Window {
+		e.Failures = 0
+	}
+	wasLocked := e.Failures >= p.LockoutThreshold
+
+	e.Failures++
+	e.LastFailureAt = now
+
+	if e.Failures >= p.LockoutThreshold {
+		e.BlockedUntil = now.Add(p.LockoutDuration)
+		return !wasLocked
+	}
+	if delay := p.delay(e.Failures); delay > 0 {
+		e.BlockedUntil = now.Add(delay)
+	}
+	return false
+}
+
+func (p Policy) delay(failures int) time.Duration {
+	extra := failures - p.FreeAttempts
+	if extra <= 0 {
+		return 0
+	}
+	if extra > 30 {
+		return p.MaxDelay
+	}
+	d := p.BaseDelay << (extra - 1)
+	if d > p.MaxDelay || d <= 0 {
+		return p.MaxDelay
+	}
+	return d
+}
@@ -0,0 +1,70 @@
+package throttle
+
+import (
+	"context"
+	"errors"
+
+	"backend/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PostgresStore keeps counters in the login_attempts table, using row
+// locks to serialise updates across server replicas
+type PostgresStore struct {
+	db *gorm.DB
+}
+
+func NewPostgresStore(db *gorm.DB) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+func (s *PostgresStore) Get(ctx context.Context, key string) (Entry, error) {
+	var attempt models.LoginAttempt
+	err := s.db.WithContext(ctx).Where("key = ?", key).First(&attempt).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return Entry{}, nil
+	}
+	if err != nil {
+		return Entry{}, err
+	}
+	return toEntry(attempt), nil
+}
+
+func (s *PostgresStore) Update(ctx context.Context, key string, fn func(*Entry)) (Entry, error) {
+	var entry Entry
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&models.LoginAttempt{Key: key}).Error; err != nil {
+			return err
+		}
+
+		var attempt models.LoginAttempt
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("key = ?", key).First(&attempt).Error; err != nil {
+			return err
+		}
+
+		entry = toEntry(attempt)
+		fn(&entry)
+
+		attempt.Failures = entry.Failures
+		attempt.LastFailureAt = entry.LastFailureAt
+		attempt.BlockedUntil = entry.BlockedUntil
+		return tx.Save(&attempt).Error
+	})
+	return entry, err
+}
+
+func (s *PostgresStore) Delete(ctx context.Context, key string) error {
+	return s.db.WithContext(ctx).Where("key = ?", key).Delete(&models.LoginAttempt{}).Error
+}
+
+func toEntry(attempt models.LoginAttempt) Entry {
+	return Entry{
+		Failures:      attempt.Failures,
+		LastFailureAt: attempt.LastFailureAt,
+		BlockedUntil:  attempt.BlockedUntil,
+	}
+}
@@ -0,0 +1,73 @@
+package throttle
+
+import (
+	"context"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"backend/database"
+)
+
+// Entry is the failure state kept for a single key
+type Entry struct {
+	Failures      int
+	LastFailureAt time.Time
+	BlockedUntil  time.Time
+}
+
+// Store persists failure counters. Update must apply fn atomically so
+// concurrent failures on the same key are all counted.
+type Store interface {
+	Get(ctx context.Context, key string) (Entry, error)
+	Update(ctx context.Context, key string, fn func(*Entry)) (Entry, error)
+	Delete(ctx context.Context, key string) error
+}
+
+var store Store = NewMemoryStore()
+
+// InitStore selects the counter store from THROTTLE_STORE. Postgres is
+// the default so counters are shared between server replicas; "memory"
+// keeps them per process.
+func InitStore() {
+	if strings.ToLower(os.Getenv("THROTTLE_STORE")) == "memory" {
+		log.Println("Login throttling: using in-memory store")
+		store = NewMemoryStore()
+		return
+	}
+	store = NewPostgresStore(database.DB)
+}
+
+// MemoryStore keeps counters in process memory
+type MemoryStore struct {
+	mu      sync.Mutex
+	entries map[string]Entry
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{entries: make(map[string]Entry)}
+}
+
+func (s *MemoryStore) Get(ctx context.Context, key string) (Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.entries[key], nil
+}
+
+func (s *MemoryStore) Update(ctx context.Context, key string, fn func(*Entry)) (Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry := s.entries[key]
+	fn(&entry)
+	s.entries[key] = entry
+	return entry, nil
+}
+
+func (s *MemoryStore) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.entries, key)
+	return nil
+}
@@ -1,35 +1,95 @@
 package utils
 
 import (
+	"errors"
+	"log"
 	"os"
 	"time"
 
 	"github.com/golang-jwt/jwt"
 )
 
-var jwtSecret []byte
+const (
+	mfaPurpose  = "mfa_challenge"
+	mfaTokenTTL = 5 * time.Minute
+)
+
+var (
+	keyRing         *KeyRing
+	accessTokenTTL  = 15 * time.Minute
+	refreshTokenTTL = 7 * 24 * time.Hour
+)
 
 func InitJWT() {
-	secret := os.Getenv("JWT_SECRET")
-	if secret == "" {
-		panic("JWT_SECRET environment variable not set")
+	ring, err := loadKeyRing()
+	if err != nil {
+		panic("Failed to load JWT signing keys: " + err.Error())
 	}
-	jwtSecret = []byte(secret)
+	keyRing = ring
+	log.Printf("JWT signing with key %s (%s), %d key(s) accepted", ring.active.ID, ring.active.Method.Alg(), len(ring.keys))
+
+	accessTokenTTL = durationFromEnv("ACCESS_TOKEN_TTL", accessTokenTTL)
+	refreshTokenTTL = durationFromEnv("REFRESH_TOKEN_TTL", refreshTokenTTL)
+}
+
+// AccessTokenTTL returns how long issued access tokens stay valid
+func AccessTokenTTL() time.Duration {
+	return accessTokenTTL
 }
 
-// Changed adminID type to uint
-func GenerateToken(adminID uint) (string, error) {
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+// RefreshTokenTTL returns how long a session can go without being refreshed
+func RefreshTokenTTL() time.Duration {
+	return refreshTokenTTL
+}
+
+// JWKS returns the public verification keys for /.well-known/jwks.json
+func JWKS() []map[string]string {
+	return keyRing.JWKS()
+}
+
+// GenerateToken issues a short-lived access token bound to a session
+func GenerateToken(adminID, sessionID uint, role string) (string, error) {
+	now := time.Now()
+	return keyRing.sign(jwt.MapClaims{
 		"admin_id": adminID,
-		"exp":      time.Now().Add(24 * time.Hour).Unix(),
+		"sid":      sessionID,
+		"role":     role,
+		"iat":      now.Unix(),
+		"exp":      now.Add(accessTokenTTL).Unix(),
 	})
-	return token.SignedString(jwtSecret)
 }
 
-func ValidateToken(tokenString string) (jwt.MapClaims, error) {
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		return jwtSecret, nil
+// GenerateMFAToken issues the short-lived challenge token returned by the
+// first login step to accounts with two-factor authentication enabled.
+// It carries no session and is rejected by RequireAuth.
+func GenerateMFAToken(adminID uint) (string, error) {
+	now := time.Now()
+	return keyRing.sign(jwt.MapClaims{
+		"admin_id": adminID,
+		"purpose":  mfaPurpose,
+		"iat":      now.Unix(),
+		"exp":      now.Add(mfaTokenTTL).Unix(),
 	})
+}
+
+// ValidateMFAToken returns the admin ID from a valid MFA challenge token
+func ValidateMFAToken(tokenString string) (uint, error) {
+	claims, err := ValidateToken(tokenString)
+	if err != nil {
+		return 0, err
+	}
+	if purpose, _ := claims["purpose"].(string); purpose != mfaPurpose {
+		return 0, errors.New("not an MFA challenge token")
+	}
+	adminID, ok := claims["admin_id"].(float64)
+	if !ok {
+		return 0, errors.New("token missing admin_id")
+	}
+	return uint(adminID), nil
+}
+
+func ValidateToken(tokenString string) (jwt.MapClaims, error) {
+	token, err := jwt.Parse(tokenString, keyRing.keyFunc)
 
 	if err != nil {
 		return nil, err
@@ -41,3 +101,16 @@ func ValidateToken(tokenString string) (jwt.MapClaims, error) {
 
 	return nil, jwt.ErrInvalidKey
 }
+
+func durationFromEnv(key string, fallback time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		log.Printf("Warning: invalid %s %q, using default %s", key, value, fallback)
+		return fallback
+	}
+	return d
+}
@@ -0,0 +1,234 @@
+package utils
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/golang-jwt/jwt"
+)
+
+// SigningKey is one entry of the key ring. Verify-only keys (old keys
+// kept during rotation, or public keys of other issuers) have no signKey.
+type SigningKey struct {
+	ID        string
+	Method    jwt.SigningMethod
+	signKey   interface{}
+	verifyKey interface{}
+}
+
+// KeyRing holds every key tokens may be verified with and the single
+// active key new tokens are signed with
+type KeyRing struct {
+	active *SigningKey
+	keys   map[string]*SigningKey
+}
+
+// keyFileConfig is the format of the file named by JWT_KEYS_FILE:
+//
+//	{
+//	  "active": "2025-10",
+//	  "keys": [
+//	    {"kid": "2025-10", "alg": "EdDSA", "private_key_file": "keys/2025-10.pem"},
+//	    {"kid": "2025-04", "alg": "RS256", "public_key_file": "keys/2025-04.pub.pem"},
+//	    {"kid": "legacy", "alg": "HS256", "secret_env": "JWT_SECRET"}
+//	  ]
+//	}
+//
+// Relative key paths are resolved against the config file's directory.
+type keyFileConfig struct {
+	Active string `json:"active"`
+	Keys   []struct {
+		ID             string `json:"kid"`
+		Algorithm      string `json:"alg"`
+		SecretEnv      string `json:"secret_env"`
+		PrivateKeyFile string `json:"private_key_file"`
+		PublicKeyFile  string `json:"public_key_file"`
+	} `json:"keys"`
+}
+
+// loadKeyRing builds the key ring from JWT_KEYS_FILE, or from JWT_SECRET
+// as a single HS256 key when no file is configured
+func loadKeyRing() (*KeyRing, error) {
+	path := os.Getenv("JWT_KEYS_FILE")
+	if path == "" {
+		secret := os.Getenv("JWT_SECRET")
+		if secret == "" {
+			return nil, errors.New("JWT_SECRET environment variable not set")
+		}
+		key := &SigningKey{
+			ID:        hmacKeyID(secret),
+			Method:    jwt.SigningMethodHS256,
+			signKey:   []byte(secret),
+			verifyKey: []byte(secret),
+		}
+		return &KeyRing{active: key, keys: map[string]*SigningKey{key.ID: key}}, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading JWT_KEYS_FILE: %w", err)
+	}
+	var cfg keyFileConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("parsing JWT_KEYS_FILE: %w", err)
+	}
+
+	ring := &KeyRing{keys: make(map[string]*SigningKey)}
+	baseDir := filepath.Dir(path)
+	for _, entry := range cfg.Keys {
+		if entry.ID == "" {
+			return nil, errors.New("every key needs a kid")
+		}
+		if _, dup := ring.keys[entry.ID]; dup {
+			return nil, fmt.Errorf("duplicate kid %q", entry.ID)
+		}
+
+		key := &SigningKey{ID: entry.ID}
+		switch entry.Algorithm {
+		case "HS256":
+			secret := os.Getenv(entry.SecretEnv)
+			if entry.SecretEnv == "" || secret == "" {
+				return nil, fmt.Errorf("key %q: secret_env must name a non-empty variable", entry.ID)
+			}
+			key.Method = jwt.SigningMethodHS256
+			key.signKey, key.verifyKey = []byte(secret), []byte(secret)
+
+		case "RS256":
+			key.Method = jwt.SigningMethodRS256
+			if entry.PrivateKeyFile != "" {
+				pem, err := readKeyFile(baseDir, entry.PrivateKeyFile)
+				if err != nil {
+					return nil, fmt.Errorf("key %q: %w", entry.ID, err)
+				}
+				private, err := jwt.ParseRSAPrivateKeyFromPEM(pem)
+				if err != nil {
+					return nil, fmt.Errorf("key %q: %w", entry.ID, err)
+				}
+				key.signKey, key.verifyKey = private, &private.PublicKey
+			} else {
+				pem, err := readKeyFile(baseDir, entry.PublicKeyFile)
+				if err != nil {
+					return nil, fmt.Errorf("key %q: %w", entry.ID, err)
+				}
+				if key.verifyKey, err = jwt.ParseRSAPublicKeyFromPEM(pem); err != nil {
+					return nil, fmt.Errorf("key %q: %w", entry.ID, err)
+				}
+			}
+
+		case "EdDSA":
+			key.Method = jwt.SigningMethodEdDSA
+			if entry.PrivateKeyFile != "" {
+				pem, err := readKeyFile(baseDir, entry.PrivateKeyFile)
+				if err != nil {
+					return nil, fmt.Errorf("key %q: %w", entry.ID, err)
+				}
+				private, err := jwt.ParseEdPrivateKeyFromPEM(pem)
+				if err != nil {
+					return nil, fmt.Errorf("key %q: %w", entry.ID, err)
+				}
+				key.signKey = private
+				key.verifyKey = private.(ed25519.PrivateKey).Public()
+			} else {
+				pem, err := readKeyFile(baseDir, entry.PublicKeyFile)
+				if err != nil {
+					return nil, fmt.Errorf("key %q: %w", entry.ID, err)
+				}
+				if key.verifyKey, err = jwt.ParseEdPublicKeyFromPEM(pem); err != nil {
+					return nil, fmt.Errorf("key %q: %w", entry.ID, err)
+				}
+			}
+
+		default:
+			return nil, fmt.Errorf("key %q: unsupported alg %q", entry.ID, entry.Algorithm)
+		}
+
+		ring.keys[key.ID] = key
+	}
+
+	active, ok := ring.keys[cfg.Active]
+	if !ok {
+		return nil, fmt.Errorf("active kid %q is not in the key ring", cfg.Active)
+	}
+	if active.signKey == nil {
+		return nil, fmt.Errorf("active kid %q has no private key", cfg.Active)
+	}
+	ring.active = active
+
+	return ring, nil
+}
+
+// sign signs claims with the active key and stamps its kid
+func (r *KeyRing) sign(claims jwt.MapClaims) (string, error) {
+	token := jwt.NewWithClaims(r.active.Method, claims)
+	token.Header["kid"] = r.active.ID
+	return token.SignedString(r.active.signKey)
+}
+
+// keyFunc resolves the verification key from the kid header and refuses
+// any algorithm other than the one registered for that key
+func (r *KeyRing) keyFunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, ok := r.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method %s", token.Method.Alg())
+	}
+	return key.verifyKey, nil
+}
+
+// JWKS returns the public keys of the ring as a JSON Web Key Set.
+// HMAC keys are shared secrets and are never published.
+func (r *KeyRing) JWKS() []map[string]string {
+	keys := make([]map[string]string, 0, len(r.keys))
+	for _, key := range r.keys {
+		jwk := map[string]string{
+			"kid": key.ID,
+			"alg": key.Method.Alg(),
+			"use": "sig",
+		}
+		switch pub := key.verifyKey.(type) {
+		case *rsa.PublicKey:
+			jwk["kty"] = "RSA"
+			jwk["n"] = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+			jwk["e"] = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk["kty"] = "OKP"
+			jwk["crv"] = "Ed25519"
+			jwk["x"] = base64.RawURLEncoding.EncodeToString(pub)
+		default:
+			continue
+		}
+		keys = append(keys, jwk)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i]["kid"] < keys[j]["kid"] })
+	return keys
+}
+
+func readKeyFile(baseDir, path string) ([]byte, error) {
+	if path == "" {
+		return nil, errors.New("private_key_file or public_key_file is required")
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(baseDir, path)
+	}
+	return os.ReadFile(path)
+}
+
+// hmacKeyID derives a stable kid from a secret without revealing it, so
+// changing JWT_SECRET also changes the kid
+func hmacKeyID(secret string) string {
+	sum := sha256.Sum256([]byte("kid:" + secret))
+	return "hs-" + hex.EncodeToString(sum[:4])
+}
@@ -0,0 +1,146 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+const maxSlugLength = 80
+
+// Devanagari consonants carry an inherent "a" that a following vowel
+// sign replaces and a virama removes
+var devanagariConsonants = map[rune]string{
+	'क': "k", 'ख': "kh", 'ग': "g", 'घ': "gh", 'ङ': "ng",
+	'च': "ch", 'छ': "chh", 'ज': "j", 'झ': "jh", 'ञ': "ny",
+	'ट': "t", 'ठ': "th", 'ड': "d", 'ढ': "dh", 'ण': "n",
+	'त': "t", 'थ': "th", 'द': "d", 'ध': "dh", 'न': "n",
+	'प': "p", 'फ': "ph", 'ब': "b", 'भ': "bh", 'म': "m",
+	'य': "y", 'र': "r", 'ल': "l", 'व': "v",
+	'श': "sh", 'ष': "sh", 'स': "s", 'ह': "h",
+}
+
+var devanagariVowels = map[rune]string{
+	'अ': "a", 'आ': "a", 'इ': "i", 'ई': "i", 'उ': "u", 'ऊ': "u",
+	'ऋ': "ri", 'ए': "e", 'ऐ': "ai", 'ओ': "o", 'औ': "au",
+}
+
+var devanagariVowelSigns = map[rune]string{
+	'ा': "a", 'ि': "i", 'ी': "i", 'ु': "u", 'ू': "u",
+	'ृ': "ri", 'े': "e", 'ै': "ai", 'ो': "o", 'ौ': "au",
+}
+
+const (
+	devanagariVirama = '्'
+	devanagariNukta  = '़'
+)
+
+// Slugify turns a title into a URL slug: Devanagari is transliterated,
+// accents are stripped from Latin letters, and everything else that
+// isn't a letter or digit becomes a single hyphen
+func Slugify(title string) string {
+	latin := stripMarks(transliterateDevanagari(title))
+
+	var sb strings.Builder
+	hyphen := false
+	for _, r := range strings.ToLower(latin) {
+		switch {
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			sb.WriteRune(r)
+			hyphen = false
+		case !hyphen && sb.Len() > 0:
+			sb.WriteByte('-')
+			hyphen = true
+		}
+	}
+
+	slug := strings.Trim(sb.String(), "-")
+	if len(slug) > maxSlugLength {
+		slug = strings.Trim(slug[:maxSlugLength], "-")
+		if i := strings.LastIndexByte(slug, '-'); i > maxSlugLength/2 {
+			slug = slug[:i]
+		}
+	}
+	return slug
+}
+
+// IsNumericSlug reports whether slug is only digits, which routes that
+// accept either an ID or a slug would read as an ID
+func IsNumericSlug(slug string) bool {
+	if slug == "" {
+		return false
+	}
+	for _, r := range slug {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// transliterateDevanagari writes Nepali/Hindi script in Latin letters.
+// The inherent vowel is dropped at the end of a word, as it is silent in
+// Nepali ("नेपाल" becomes "nepal").
+func transliterateDevanagari(s string) string {
+	var sb strings.Builder
+	pendingA := false
+
+	flush := func(endOfWord bool) {
+		if pendingA && !endOfWord {
+			sb.WriteByte('a')
+		}
+		pendingA = false
+	}
+
+	for _, r := range s {
+		if consonant, ok := devanagariConsonants[r]; ok {
+			flush(false)
+			sb.WriteString(consonant)
+			pendingA = true
+			continue
+		}
+		if sign, ok := devanagariVowelSigns[r]; ok {
+			pendingA = false
+			sb.WriteString(sign)
+			continue
+		}
+
+		switch {
+		case r == devanagariVirama:
+			pendingA = false
+		case r == devanagariNukta:
+		case r == 'ं' || r == 'ँ':
+			flush(false)
+			sb.WriteByte('n')
+		case r == 'ः':
+			flush(false)
+			sb.WriteByte('h')
+		case r >= '०' && r <= '९':
+			flush(true)
+			sb.WriteRune('0' + (r - '०'))
+		default:
+			if vowel, ok := devanagariVowels[r]; ok {
+				flush(false)
+				sb.WriteString(vowel)
+				continue
+			}
+			flush(true)
+			sb.WriteRune(r)
+		}
+	}
+	flush(true)
+
+	return sb.String()
+}
+
+// stripMarks removes combining accents, e.g. "Café" becomes "Cafe"
+func stripMarks(s string) string {
+	var sb strings.Builder
+	for _, r := range norm.NFD.String(s) {
+		if !unicode.Is(unicode.Mn, r) {
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
@@ -135,12 +135,12 @@ export default function BlogSection() {
                     </span>
                     <span className="flex items-center">
                       <FiClock className="mr-1.5" />
-                      {article.readTime || "5 min read"}
+                      {article.reading_time ? `${article.reading_time} min read` : "5 min read"}
                     </span>
                   </div>
                   <h3 className="text-xl font-bold text-gray-800 mb-3 line-clamp-2">
                     <Link 
-                      href={`/blog/${article.id}`} 
+                      href={`/blog/${article.slug || article.id}`} 
                       className="hover:text-green-600 transition-colors"
                       aria-label={`Read more about ${article.title}`}
                     >
@@ -151,7 +151,7 @@ export default function BlogSection() {
                     {article.excerpt}
                   </p>
                   <Link 
-                    href={`/blog/${article.id}`} 
+                    href={`/blog/${article.slug || article.id}`} 
                     className="inline-flex items-center text-green-600 font-medium text-sm hover:text-green-700 transition-colors"
                     aria-label={`Read more about ${article.title}`}
                   >
@@ -0,0 +1,140 @@
+import { useCallback, useEffect, useState } from 'react';
+import { useRouter } from 'next/router';
+import { blogApi } from '@/utils/api';
+
+const QUEUES = [
+  { status: 'pending', label: 'Pending' },
+  { status: 'approved', label: 'Approved' },
+  { status: 'spam', label: 'Spam' },
+];
+
+// Moves offered for a comment in each queue
+const MODERATION_ACTIONS = {
+  pending: [{ status: 'approved', label: 'Approve' }, { status: 'spam', label: 'Spam' }],
+  approved: [{ status: 'pending', label: 'Unapprove' }, { status: 'spam', label: 'Spam' }],
+  spam: [{ status: 'approved', label: 'Not spam' }],
+};
+
+export default function CommentModeration() {
+  const router = useRouter();
+  const [queue, setQueue] = useState('pending');
+  const [comments, setComments] = useState([]);
+  const [counts, setCounts] = useState({});
+  const [allowed, setAllowed] = useState(true);
+  const [busyId, setBusyId] = useState(null);
+  const [error, setError] = useState('');
+
+  // Notification emails link here with ?status=pending&blog=<id>
+  const blog = router.query.blog;
+  useEffect(() => {
+    const status = router.query.status;
+    if (QUEUES.some((q) => q.status === status)) setQueue(status);
+  }, [router.query.status]);
+
+  const load = useCallback(async () => {
+    try {
+      const params = { status: queue };
+      if (blog) params.blog = blog;
+      const result = await blogApi.listModerationComments(params);
+      setComments(result?.data || []);
+      setCounts(result?.counts || {});
+      setError('');
+    } catch (err) {
+      // Authors and viewers can't moderate; leave the panel out for them
+      if (err.status === 403) {
+        setAllowed(false);
+        return;
+      }
+      setError(err.data?.error || err.message || 'Failed to load comments');
+    }
+  }, [queue, blog]);
+
+  useEffect(() => {
+    load();
+  }, [load]);
+
+  const act = async (comment, action) => {
+    setBusyId(comment.id);
+    try {
+      if (action === 'delete') {
+        if (!window.confirm('Delete this comment and all replies to it?')) return;
+        await blogApi.deleteComment(comment.id);
+      } else {
+        await blogApi.moderateComment(comment.id, action);
+      }
+      await load();
+    } catch (err) {
+      setError(err.data?.error || err.message || 'Failed to update comment');
+    } finally {
+      setBusyId(null);
+    }
+  };
+
+  if (!allowed) return null;
+
+  return (
+    <div className="bg-white shadow overflow-hidden sm:rounded-lg mt-8">
+      <div className="px-4 py-5 sm:px-6 flex items-center justify-between">
+        <h3 className="text-lg leading-6 font-medium text-gray-900">Comments</h3>
+        <div className="flex space-x-2">
+          {QUEUES.map(({ status, label }) => (
+            <button
+              key={status}
+              onClick={() => setQueue(status)}
+              className={`px-3 py-1 rounded-full text-sm ${
+                queue === status ? 'bg-indigo-600 text-white' : 'bg-gray-100 text-gray-700 hover:bg-gray-200'
+              }`}
+            >
+              {label} ({counts[status] || 0})
+            </button>
+          ))}
+        </div>
+      </div>
+
+      {error && (
+        <div className="mx-6 mb-4 bg-red-100 border border-red-400 text-red-700 px-4 py-3 rounded">{error}</div>
+      )}
+
+      <ul className="divide-y divide-gray-200 border-t border-gray-200">
+        {comments.length === 0 ? (
+          <li className="px-6 py-4 text-center text-sm text-gray-500">No comments here.</li>
+        ) : (
+          comments.map((comment) => (
+            <li key={comment.id} className="px-6 py-4">
+              <div className="flex justify-between text-sm">
+                <span className="font-medium text-gray-900">
+                  {comment.author_name} <span className="text-gray-500">&lt;{comment.author_email}&gt;</span>
+                </span>
+                <span className="text-gray-500">{new Date(comment.created_at).toLocaleString()}</span>
+              </div>
+              <p className="text-xs text-gray-500 mt-1">
+                On <a href={`/blog/${comment.blog_slug || comment.blog_id}`} className="text-indigo-600 hover:underline">{comment.blog_title}</a>
+                {comment.parent_id && ' (reply)'}
+              </p>
+              <p className="mt-2 text-gray-700 whitespace-pre-line">{comment.body}</p>
+              <div className="mt-2 flex space-x-3 text-sm">
+                {(MODERATION_ACTIONS[comment.status] || []).map((action) => (
+                  <button
+                    key={action.status}
+                    onClick={() => act(comment, action.status)}
+                    disabled={busyId === comment.id}
+                    className="text-indigo-600 hover:text-indigo-900 disabled:opacity-50"
+                  >
+                    {action.label}
+                  </button>
+                ))}
+                <button
+                  onClick={() => act(comment, 'delete')}
+                  disabled={busyId === comment.id}
+                  className="text-red-600 hover:text-red-900 disabled:opacity-50"
+                >
+                  Delete
+                </button>
+              </div>
+            </li>
+          ))
+        )}
+      </ul>
+    </div>
+  );
+}
@@ -0,0 +1,190 @@
+import { useEffect, useState } from "react";
+import { blogApi } from "../utils/api";
+import { FiMessageSquare, FiCornerDownRight } from "react-icons/fi";
+
+const EMPTY_FORM = { name: "", email: "", body: "", website: "" };
+
+function CommentForm({ blogId, parentId, onDone }) {
+  const [form, setForm] = useState(EMPTY_FORM);
+  const [sending, setSending] = useState(false);
+  const [message, setMessage] = useState(null);
+
+  const handleChange = (e) => {
+    const { name, value } = e.target;
+    setForm((prev) => ({ ...prev, [name]: value }));
+  };
+
+  const handleSubmit = async (e) => {
+    e.preventDefault();
+    setSending(true);
+    setMessage(null);
+    try {
+      await blogApi.submitComment(blogId, { ...form, parentId });
+      setForm(EMPTY_FORM);
+      setMessage({ ok: true, text: "Thanks! Your comment will appear once it has been approved." });
+      onDone?.();
+    } catch (error) {
+      setMessage({ ok: false, text: error.data?.error || error.message || "Failed to submit comment" });
+    } finally {
+      setSending(false);
+    }
+  };
+
+  return (
+    <form onSubmit={handleSubmit} className="space-y-3">
+      <div className="grid grid-cols-1 sm:grid-cols-2 gap-3">
+        <input
+          name="name"
+          required
+          maxLength={100}
+          value={form.name}
+          onChange={handleChange}
+          placeholder="Name"
+          className="px-3 py-2 border border-gray-300 rounded-md focus:outline-none focus:ring-2 focus:ring-green-500"
+        />
+        <input
+          name="email"
+          type="email"
+          required
+          maxLength={255}
+          value={form.email}
+          onChange={handleChange}
+          placeholder="Email (not published)"
+          className="px-3 py-2 border border-gray-300 rounded-md focus:outline-none focus:ring-2 focus:ring-green-500"
+        />
+      </div>
+      {/* Hidden from people; bots that fill it in are ignored */}
+      <input
+        name="website"
+        tabIndex={-1}
+        autoComplete="off"
+        value={form.website}
+        onChange={handleChange}
+        className="hidden"
+        aria-hidden="true"
+      />
+      <textarea
+        name="body"
+        required
+        rows={4}
+        maxLength={5000}
+        value={form.body}
+        onChange={handleChange}
+        placeholder={parentId ? "Write a reply" : "Ask a question or share your experience"}
+        className="block w-full px-3 py-2 border border-gray-300 rounded-md focus:outline-none focus:ring-2 focus:ring-green-500"
+      />
+      {message && (
+        <p className={`text-sm ${message.ok ? "text-green-700" : "text-red-600"}`}>{message.text}</p>
+      )}
+      <button
+        type="submit"
+        disabled={sending}
+        className="px-4 py-2 bg-green-600 text-white rounded-lg hover:bg-green-700 transition-colors disabled:opacity-50"
+      >
+        {sending ? "Sending..." : parentId ? "Post reply" : "Post comment"}
+      </button>
+    </form>
+  );
+}
+
+function CommentThread({ comment, blogId, replyTo, setReplyTo }) {
+  return (
+    <li className="mt-4">
+      <div className="bg-gray-50 rounded-lg p-4">
+        <div className="flex items-center justify-between mb-1">
+          <span className="font-medium text-gray-900">{comment.author_name}</span>
+          <time className="text-xs text-gray-500" dateTime={comment.created_at}>
+            {new Date(comment.created_at).toLocaleDateString("en-US", {
+              year: "numeric",
+              month: "short",
+              day: "numeric",
+            })}
+          </time>
+        </div>
+        <p className="text-gray-700 whitespace-pre-line">{comment.body}</p>
+        <button
+          onClick={() => setReplyTo(replyTo === comment.id ? null : comment.id)}
+          className="mt-2 flex items-center text-sm text-green-600 hover:text-green-800"
+        >
+          <FiCornerDownRight className="mr-1" /> Reply
+        </button>
+        {replyTo === comment.id && (
+          <div className="mt-3">
+            <CommentForm blogId={blogId} parentId={comment.id} />
+          </div>
+        )}
+      </div>
+      {comment.replies?.length > 0 && (
+        <ul className="ml-6 border-l border-gray-200 pl-4">
+          {comment.replies.map((reply) => (
+            <CommentThread
+              key={reply.id}
+              comment={reply}
+              blogId={blogId}
+              replyTo={replyTo}
+              setReplyTo={setReplyTo}
+            />
+          ))}
+        </ul>
+      )}
+    </li>
+  );
+}
+
+export default function Comments({ blogId }) {
+  const [comments, setComments] = useState([]);
+  const [count, setCount] = useState(0);
+  const [showForm, setShowForm] = useState(false);
+  const [replyTo, setReplyTo] = useState(null);
+
+  useEffect(() => {
+    if (!blogId) return;
+    blogApi
+      .listComments(blogId)
+      .then((result) => {
+        setComments(result?.data || []);
+        setCount(result?.count || 0);
+      })
+      .catch((error) => console.error("Error fetching comments:", error));
+  }, [blogId]);
+
+  return (
+    <section className="mb-12">
+      <div className="flex items-center justify-between mb-6">
+        <h2 className="text-xl font-bold text-gray-900">
+          Comments{count > 0 ? ` (${count})` : ""}
+        </h2>
+        <button
+          onClick={() => setShowForm(!showForm)}
+          className="flex items-center text-green-600 hover:text-green-800"
+        >
+          <FiMessageSquare className="mr-2" /> Leave a comment
+        </button>
+      </div>
+
+      {showForm && (
+        <div className="mb-6">
+          <CommentForm blogId={blogId} />
+        </div>
+      )}
+
+      {comments.length > 0 ? (
+        <ul>
+          {comments.map((comment) => (
+            <CommentThread
+              key={comment.id}
+              comment={comment}
+              blogId={blogId}
+              replyTo={replyTo}
+              setReplyTo={setReplyTo}
+            />
+          ))}
+        </ul>
+      ) : (
+        <div className="bg-gray-50 rounded-lg p-6 text-center">
+          <p className="text-gray-500">No comments yet. Be the first to ask a question.</p>
+        </div>
+      )}
+    </section>
+  );
+}
@@ -5,6 +5,8 @@ import { adminApi, adminToken } from '../utils/api';
 export default function AdminLogin() {
   const [email, setEmail] = useState('');
   const [password, setPassword] = useState('');
+  const [mfaToken, setMfaToken] = useState('');
+  const [mfaCode, setMfaCode] = useState('');
   const [error, setError] = useState('');
   const [loading, setLoading] = useState(false);
   const router = useRouter();
@@ -24,15 +26,22 @@ export default function AdminLogin() {
         throw new Error('Please enter a valid email address');
       }
 
-      // Attempt login
-      const response = await adminApi.login({ email, password });
+      // Attempt login; accounts with two-factor enabled need a second step
+      const response = mfaToken
+        ? await adminApi.loginMfa({ mfaToken, code: mfaCode })
+        : await adminApi.login({ email, password });
+
+      if (response?.mfa_required) {
+        setMfaToken(response.mfa_token);
+        return;
+      }
       
       if (!response?.token) {
         throw new Error('Authentication failed. Please check your credentials.');
       }
 
       // Store token and redirect
-      adminToken.store(response.token);
+      adminToken.store(response.token, response.refresh_token);
       router.push(router.query.returnTo || '/admin/dashboard');
 
     } catch (err) {
@@ -79,6 +88,27 @@ export default function AdminLogin() {
 
         <form className="mt-8 space-y-6" onSubmit={handleSubmit}>
           <div className="rounded-md shadow-sm space-y-4">
+            {mfaToken ? (
+            <div>
+              <label htmlFor="mfaCode" className="block text-sm font-medium text-gray-700">
+                Authentication code
+              </label>
+              <input
+                id="mfaCode"
+                name="mfaCode"
+                type="text"
+                inputMode="numeric"
+                autoComplete="one-time-code"
+                required
+                value={mfaCode}
+                onChange={(e) => setMfaCode(e.target.value.trim())}
+                className="mt-1 block w-full px-3 py-2 border border-gray-300 rounded-md shadow-sm focus:outline-none focus:ring-indigo-500 focus:border-indigo-500 sm:text-sm"
+                disabled={loading}
+              />
+              <p className="mt-1 text-xs text-gray-500">Enter the 6-digit code from your authenticator app.</p>
+            </div>
+            ) : (
+            <>
             <div>
               <label htmlFor="email" className="block text-sm font-medium text-gray-700">
                 Email address
@@ -112,6 +142,8 @@ export default function AdminLogin() {
                 minLength="8"
               />
             </div>
+            </>
+            )}
           </div>
 
           <div>
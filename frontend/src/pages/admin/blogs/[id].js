@@ -7,18 +7,34 @@ export default function EditBlogPage() {
   const { id } = router.query;
   const [title, setTitle] = useState('');
   const [content, setContent] = useState('');
+  const [slug, setSlug] = useState('');
+  const [currentSlug, setCurrentSlug] = useState('');
   const [currentImage, setCurrentImage] = useState('');
   const [newImage, setNewImage] = useState(null);
+  const [seo, setSeo] = useState({
+    meta_title: '',
+    meta_description: '',
+    canonical_url: '',
+    noindex: false,
+  });
   const [error, setError] = useState('');
   const [loading, setLoading] = useState(false);
 
   useEffect(() => {
     const fetchBlog = async () => {
       try {
-        const blog = await blogApi.getBlog(id);
+        const blog = await blogApi.getAdminBlog(id);
         setTitle(blog.title);
         setContent(blog.content);
+        setSlug(blog.slug || '');
+        setCurrentSlug(blog.slug || '');
         setCurrentImage(blog.image || '');
+        setSeo({
+          meta_title: blog.meta_title || '',
+          meta_description: blog.meta_description || '',
+          canonical_url: blog.canonical_url || '',
+          noindex: Boolean(blog.noindex),
+        });
       } catch (err) {
         console.error('Fetch error:', err);
         setError('Failed to load blog');
@@ -44,7 +60,10 @@ export default function EditBlogPage() {
       await blogApi.updateBlog(id, {
         title,
         content,
-        image: newImage
+        // Leaving the slug alone lets a new title pick a new one
+        slug: slug !== currentSlug ? slug : undefined,
+        image: newImage,
+        ...seo,
       }, token);
 
       router.push('/admin/dashboard');
@@ -56,6 +75,11 @@ export default function EditBlogPage() {
     }
   };
 
+  const handleSeoChange = (e) => {
+    const { name, value, type, checked } = e.target;
+    setSeo(prev => ({ ...prev, [name]: type === 'checkbox' ? checked : value }));
+  };
+
   const handleImageChange = (e) => {
     if (e.target.files && e.target.files[0]) {
       setNewImage(e.target.files[0]);
@@ -90,6 +114,23 @@ export default function EditBlogPage() {
               />
             </div>
 
+            <div>
+              <label htmlFor="slug" className="block text-sm font-medium text-gray-700">
+                Slug
+              </label>
+              <input
+                id="slug"
+                name="slug"
+                type="text"
+                value={slug}
+                onChange={(e) => setSlug(e.target.value)}
+                className="mt-1 block w-full px-3 py-2 border border-gray-300 rounded-md shadow-sm focus:outline-none focus:ring-indigo-500 focus:border-indigo-500"
+              />
+              <p className="mt-1 text-xs text-gray-500">
+                Old links keep working and redirect to the new slug.
+              </p>
+            </div>
+
             <div>
               <label htmlFor="content" className="block text-sm font-medium text-gray-700">
                 Content
@@ -129,6 +170,66 @@ export default function EditBlogPage() {
               />
             </div>
 
+            <fieldset className="space-y-4 border-t pt-6">
+              <legend className="text-sm font-semibold text-gray-900">Search and sharing</legend>
+              <div>
+                <label htmlFor="meta_title" className="block text-sm font-medium text-gray-700">
+                  Meta title
+                </label>
+                <input
+                  id="meta_title"
+                  name="meta_title"
+                  type="text"
+                  maxLength={70}
+                  value={seo.meta_title}
+                  onChange={handleSeoChange}
+                  placeholder={title}
+                  className="mt-1 block w-full px-3 py-2 border border-gray-300 rounded-md shadow-sm focus:outline-none focus:ring-indigo-500 focus:border-indigo-500"
+                />
+              </div>
+              <div>
+                <label htmlFor="meta_description" className="block text-sm font-medium text-gray-700">
+                  Meta description
+                </label>
+                <textarea
+                  id="meta_description"
+                  name="meta_description"
+                  rows={2}
+                  maxLength={160}
+                  value={seo.meta_description}
+                  onChange={handleSeoChange}
+                  className="mt-1 block w-full px-3 py-2 border border-gray-300 rounded-md shadow-sm focus:outline-none focus:ring-indigo-500 focus:border-indigo-500"
+                />
+                <p className="mt-1 text-xs text-gray-500">
+                  Leave blank to use the post excerpt. {seo.meta_description.length}/160
+                </p>
+              </div>
+              <div>
+                <label htmlFor="canonical_url" className="block text-sm font-medium text-gray-700">
+                  Canonical URL
+                </label>
+                <input
+                  id="canonical_url"
+                  name="canonical_url"
+                  type="url"
+                  value={seo.canonical_url}
+                  onChange={handleSeoChange}
+                  placeholder="https://"
+                  className="mt-1 block w-full px-3 py-2 border border-gray-300 rounded-md shadow-sm focus:outline-none focus:ring-indigo-500 focus:border-indigo-500"
+                />
+              </div>
+              <label className="flex items-center text-sm text-gray-700">
+                <input
+                  name="noindex"
+                  type="checkbox"
+                  checked={seo.noindex}
+                  onChange={handleSeoChange}
+                  className="mr-2"
+                />
+                Hide from search engines
+              </label>
+            </fieldset>
+
             <div className="flex justify-end space-x-3">
               <button
                 type="button"
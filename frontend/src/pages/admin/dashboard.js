@@ -2,6 +2,15 @@ import { useEffect, useState, useCallback } from 'react';
 import { useRouter } from 'next/router';
 import Image from 'next/image';
 import { adminApi, blogApi, adminToken, getImageUrl } from '@/utils/api';
+import CommentModeration from '@/components/CommentModeration';
+
+// Workflow moves offered for each status; the API enforces who may make them
+const STATUS_ACTIONS = {
+  draft: [{ status: 'review', label: 'Submit for review' }, { status: 'published', label: 'Publish' }],
+  review: [{ status: 'published', label: 'Publish' }, { status: 'draft', label: 'Back to draft' }],
+  published: [{ status: 'archived', label: 'Archive' }, { status: 'draft', label: 'Unpublish' }],
+  archived: [{ status: 'published', label: 'Republish' }],
+};
 
 export default function AdminDashboard() {
   const router = useRouter();
@@ -66,12 +75,13 @@ export default function AdminDashboard() {
         throw new Error('Session verification failed');
       }
 
-      const blogsResponse = await blogApi.getAllBlogs(token);
+      const blogsResult = await blogApi.listAdminBlogs({ limit: 100 });
+      const blogsResponse = blogsResult?.data || [];
       
       setAdminData({
-        id: sessionResponse.adminID || 'default-admin',
-        username: sessionResponse.username || `Admin ${sessionResponse.adminID || ''}`,
-        lastLogin: sessionResponse.lastLogin ? new Date(sessionResponse.lastLogin) : null
+        id: sessionResponse.admin?.id || sessionResponse.adminID,
+        username: sessionResponse.admin?.username || `Admin ${sessionResponse.adminID || ''}`,
+        lastLogin: sessionResponse.session?.created_at ? new Date(sessionResponse.session.created_at) : null
       });
 
       const processedBlogs = Array.isArray(blogsResponse) ? blogsResponse.map(blog => ({
@@ -85,7 +95,7 @@ export default function AdminDashboard() {
         status: blog.status || 'draft',
         content: blog.content || '',
         slug: blog.slug || '',
-        author: blog.author || 'Admin'
+        author: blog.author?.display_name || 'Admin'
       })) : [];
 
       setBlogs(processedBlogs);
@@ -103,11 +113,28 @@ export default function AdminDashboard() {
     fetchData();
   }, [fetchData]);
 
-  const handleLogout = () => {
-    adminToken.remove();
+  const handleLogout = async () => {
+    await adminApi.logout();
     window.location.href = '/admin';
   };
 
+  const handleStatusChange = async (blogId, status) => {
+    try {
+      const result = await blogApi.updateBlogStatus(blogId, status);
+      setBlogs(prev => {
+        const updatedBlogs = prev.map(blog => (
+          blog.id === blogId ? { ...blog, status: result.blog?.status || status } : blog
+        ));
+        updateStats(updatedBlogs);
+        return updatedBlogs;
+      });
+      setError('');
+    } catch (err) {
+      console.error('Status change error:', err);
+      setError(err.message || 'Failed to change blog status');
+    }
+  };
+
   const handleDeleteBlog = async (blogId) => {
     if (!window.confirm('Are you sure you want to delete this blog post? This action cannot be undone.')) {
       return;
@@ -332,6 +359,11 @@ export default function AdminDashboard() {
                         }`}>
                           {blog.status}
                         </span>
+                        {blog.publish_at && (
+                          <div className="text-xs text-gray-500 mt-1">
+                            Goes live {new Date(blog.publish_at).toLocaleString()}
+                          </div>
+                        )}
                       </td>
                       <td className="px-6 py-4 whitespace-nowrap text-sm text-gray-500">
                         {blog.createdAt.toLocaleDateString('en-US', {
@@ -349,6 +381,15 @@ export default function AdminDashboard() {
                           >
                             Edit
                           </button>
+                          {(STATUS_ACTIONS[blog.status] || []).map(action => (
+                            <button
+                              key={action.status}
+                              onClick={() => handleStatusChange(blog.id, action.status)}
+                              className="text-green-600 hover:text-green-900"
+                            >
+                              {action.label}
+                            </button>
+                          ))}
                           <button
                             onClick={() => handleDeleteBlog(blog.id)}
                             className="text-red-600 hover:text-red-900"
@@ -379,6 +420,8 @@ export default function AdminDashboard() {
             </table>
           </div>
         </div>
+
+        <CommentModeration />
       </main>
     </div>
   );
@@ -3,7 +3,8 @@ import { useEffect, useState } from "react";
 import Navbar from "../../components/Navbar";
 import Head from "next/head";
 import { blogApi, getImageUrl } from "../../utils/api";
-import { FiCalendar, FiClock, FiArrowLeft, FiShare2, FiBookmark, FiMessageSquare } from "react-icons/fi";
+import { FiCalendar, FiClock, FiArrowLeft, FiShare2, FiBookmark } from "react-icons/fi";
+import Comments from "../../components/Comments";
 import Image from "next/image";
 import Link from "next/link";
 
@@ -31,11 +32,16 @@ export default function BlogDetailPage() {
           imageUrl: data.image ? getImageUrl(data.image) : '/default-blog.jpg'
         };
         setBlog(processedBlog);
+
+        // Old slugs and numeric IDs resolve to the post; show its current URL
+        if (data.slug && data.slug !== id) {
+          router.replace(`/blog/${data.slug}`, undefined, { shallow: true });
+        }
         
         const allPosts = await blogApi.getAllBlogs();
         setRelatedPosts(
           allPosts
-            .filter(post => post.id !== id)
+            .filter(post => post.id !== data.id)
             .slice(0, 3)
             .map(post => ({
               ...post,
@@ -133,11 +139,15 @@ export default function BlogDetailPage() {
   return (
     <>
       <Head>
-        <title>{blog.title} | Starlink Education</title>
-        <meta name="description" content={blog.excerpt || blog.content?.replace(/<[^>]*>?/gm, '').slice(0, 160)} />
-        <meta property="og:title" content={blog.title} />
-        <meta property="og:description" content={blog.excerpt || blog.content?.replace(/<[^>]*>?/gm, '').slice(0, 160)} />
-        {blog.imageUrl && <meta property="og:image" content={blog.imageUrl} />}
+        <title>{`${blog.seo?.title || blog.title} | Starlink Education`}</title>
+        <meta name="description" content={blog.seo?.description || blog.excerpt} />
+        {blog.seo?.canonical_url && <link rel="canonical" href={blog.seo.canonical_url} />}
+        {blog.seo?.noindex && <meta name="robots" content="noindex" />}
+        <meta property="og:type" content="article" />
+        <meta property="og:title" content={blog.seo?.title || blog.title} />
+        <meta property="og:description" content={blog.seo?.description || blog.excerpt} />
+        <meta property="og:image" content={blog.seo?.og_image ? getImageUrl(blog.seo.og_image) : blog.imageUrl} />
+        <meta name="twitter:card" content="summary_large_image" />
       </Head>
       <Navbar />
       
@@ -177,7 +187,7 @@ export default function BlogDetailPage() {
                     </span>
                     <span className="flex items-center">
                       <FiClock className="mr-1" /> 
-                      {blog.readTime || "5 min read"}
+                      {blog.reading_time ? `${blog.reading_time} min read` : "5 min read"}
                     </span>
                   </div>
                 </div>
@@ -252,7 +262,8 @@ export default function BlogDetailPage() {
           
           {/* Article Content */}
           <div className="prose prose-sm sm:prose-base md:prose-lg max-w-none">
-            <div dangerouslySetInnerHTML={{ __html: blog.content }} />
+            {/* content_html is sanitized by the API */}
+            <div dangerouslySetInnerHTML={{ __html: blog.content_html }} />
           </div>
           
           {/* Tags */}
@@ -262,10 +273,10 @@ export default function BlogDetailPage() {
               <div className="flex flex-wrap gap-2">
                 {blog.tags.map(tag => (
                   <span 
-                    key={tag} 
+                    key={tag.id} 
                     className="px-3 py-1 bg-gray-100 text-gray-800 text-sm rounded-full"
                   >
-                    {tag}
+                    {tag.name}
                   </span>
                 ))}
               </div>
@@ -274,17 +285,7 @@ export default function BlogDetailPage() {
         </article>
         
         {/* Comments Section */}
-        <section className="mb-12">
-          <div className="flex items-center justify-between mb-6">
-            <h2 className="text-xl font-bold text-gray-900">Comments</h2>
-            <button className="flex items-center text-green-600 hover:text-green-800">
-              <FiMessageSquare className="mr-2" /> Leave a comment
-            </button>
-          </div>
-          <div className="bg-gray-50 rounded-lg p-6 text-center">
-            <p className="text-gray-500">Comments feature coming soon</p>
-          </div>
-        </section>
+        <Comments blogId={blog.id} />
         
         {/* Related Articles */}
         {relatedPosts.length > 0 && (
@@ -293,7 +294,7 @@ export default function BlogDetailPage() {
             <div className="grid grid-cols-1 sm:grid-cols-2 lg:grid-cols-3 gap-6">
               {relatedPosts.map(post => (
                 <article key={post.id} className="bg-white rounded-lg overflow-hidden shadow-md hover:shadow-lg transition-shadow">
-                  <Link href={`/blog/${post.id}`} className="block">
+                  <Link href={`/blog/${post.slug || post.id}`} className="block">
                     <div className="relative w-full aspect-video">
                       <Image
                         src={post.imageUrl}
@@ -312,7 +313,7 @@ export default function BlogDetailPage() {
                         </span>
                         <span className="flex items-center">
                           <FiClock className="mr-1" /> 
-                          {post.readTime || "5 min read"}
+                          {post.reading_time ? `${post.reading_time} min read` : "5 min read"}
                         </span>
                       </div>
                     </div>
@@ -2,7 +2,7 @@ import Head from "next/head";
 import Navbar from "../../components/Navbar";
 import Link from "next/link";
 import { useEffect, useState } from "react";
-import { blogApi, getImageUrl } from "../../utils/api";
+import { blogApi, getFeedUrl, getImageUrl } from "../../utils/api";
 import {
   FiCalendar,
   FiClock,
@@ -18,35 +18,27 @@ export default function BlogPage() {
   const [filteredBlogs, setFilteredBlogs] = useState([]);
   const [loading, setLoading] = useState(true);
   const [searchQuery, setSearchQuery] = useState("");
+  const [searchResults, setSearchResults] = useState(null);
   const [currentPage, setCurrentPage] = useState(1);
   const [sortOption, setSortOption] = useState("newest");
   const postsPerPage = 6;
 
-  // Sample categories
-  const categories = [
-    { id: 1, name: "Visa Information" },
-    { id: 2, name: "University Guides" },
-    { id: 3, name: "Scholarships" },
-    { id: 4, name: "Student Life" },
-    { id: 5, name: "Career Advice" },
-  ];
+  const [categories, setCategories] = useState([]);
   const [selectedCategory, setSelectedCategory] = useState(null);
 
   useEffect(() => {
     async function fetchBlogs() {
       try {
-        const data = await blogApi.getAllBlogs();
+        const [data, categoryTree] = await Promise.all([
+          blogApi.getAllBlogs(),
+          blogApi.listCategories().catch(() => []),
+        ]);
+        setCategories(categoryTree);
         const processedBlogs = data.map((blog) => ({
           ...blog,
           createdAt: blog.createdAt ? new Date(blog.createdAt) : new Date(),
-
           imageUrl: blog.image ? getImageUrl(blog.image) : "/default-blog.jpg",
-          // Add a sample category for demo purposes
-          category: categories[Math.floor(Math.random() * categories.length)],
-
-          imageUrl: blog.image ? getImageUrl(blog.image) : '/default-blog.jpg',
-          category: categories[Math.floor(Math.random() * categories.length)]
-
+          category: blog.categories?.[0] || null,
         }));
         setBlogs(processedBlogs);
         setFilteredBlogs(processedBlogs);
@@ -59,28 +51,48 @@ export default function BlogPage() {
     fetchBlogs();
   }, []);
 
+  // Search on the server, debounced, so results are ranked and typos tolerated
   useEffect(() => {
-    let results = [...blogs];
+    const q = searchQuery.trim();
+    if (!q) {
+      setSearchResults(null);
+      return;
+    }
 
+    const timer = setTimeout(async () => {
+      try {
+        const result = await blogApi.searchBlogs(q, { limit: 100 });
+        setSearchResults(result?.data || []);
+      } catch (error) {
+        console.error("Error searching blogs:", error);
+      }
+    }, 300);
+    return () => clearTimeout(timer);
+  }, [searchQuery]);
 
-    // Apply search filter
-    
+  useEffect(() => {
+    let results = [...blogs];
 
-    if (searchQuery) {
-      results = results.filter(
-        (blog) =>
-          blog.title.toLowerCase().includes(searchQuery.toLowerCase()) ||
-          blog.content.toLowerCase().includes(searchQuery.toLowerCase())
-      );
+    if (searchResults) {
+      const byId = new Map(blogs.map((blog) => [blog.id, blog]));
+      results = searchResults
+        .filter((hit) => byId.has(hit.id))
+        .map((hit) => ({ ...byId.get(hit.id), snippet: hit.snippet }));
     }
 
     if (selectedCategory) {
-      results = results.filter(
-        (blog) => blog.category && blog.category.id === selectedCategory.id
+      // A top-level category also matches posts filed under its children
+      const ids = new Set([
+        selectedCategory.id,
+        ...(selectedCategory.children || []).map((child) => child.id),
+      ]);
+      results = results.filter((blog) =>
+        (blog.categories || []).some((category) => ids.has(category.id))
       );
     }
 
-    switch(sortOption) {
+    // Search results keep their relevance order
+    switch(searchResults ? "relevance" : sortOption) {
 
       case "newest":
         results.sort((a, b) => b.createdAt - a.createdAt);
@@ -97,7 +109,7 @@ export default function BlogPage() {
 
     setFilteredBlogs(results);
     setCurrentPage(1);
-  }, [blogs, searchQuery, selectedCategory, sortOption]);
+  }, [blogs, searchResults, selectedCategory, sortOption]);
 
   function formatDate(date) {
     try {
@@ -136,6 +148,9 @@ export default function BlogPage() {
           name="description"
           content="Expert insights and advice on studying in Australia. Get the latest updates on visas, universities, scholarships, and student life."
         />
+        <link rel="alternate" type="application/rss+xml" title="Starlink Education blog" href={getFeedUrl("rss")} />
+        <link rel="alternate" type="application/atom+xml" title="Starlink Education blog" href={getFeedUrl("atom")} />
+        <link rel="alternate" type="application/feed+json" title="Starlink Education blog" href={getFeedUrl("json")} />
       </Head>
 
       <div className="pt-24 pb-16 bg-green-600">
@@ -274,22 +289,22 @@ export default function BlogPage() {
                       </span>
                       <span className="flex items-center">
                         <FiClock className="mr-1.5" />
-                        {post.readTime || "5 min read"}
+                        {post.reading_time ? `${post.reading_time} min read` : "5 min read"}
                       </span>
                     </div>
                     <h3 className="text-xl font-bold text-gray-800 mb-3 line-clamp-2">
                       <Link
-                        href={`/blog/${post.id}`}
+                        href={`/blog/${post.slug || post.id}`}
                         className="hover:text-green-600 transition-colors"
                       >
                         {post.title}
                       </Link>
                     </h3>
                     <p className="text-gray-600 text-sm mb-5 line-clamp-3">
-                      {post.content?.replace(/<[^>]*>?/gm, "").slice(0, 150)}...
+                      {post.excerpt}
                     </p>
                     <Link
-                      href={`/blog/${post.id}`}
+                      href={`/blog/${post.slug || post.id}`}
                       className="inline-flex items-center text-green-600 font-medium text-sm hover:text-green-700 transition-colors"
                     >
                       Read more <FiArrowRight className="ml-1.5" />
@@ -100,6 +100,45 @@ export const adminApi = {
     }
   },
 
+  loginMfa: async ({ mfaToken, code }) => {
+    const response = await fetchWithTimeout(`${API_BASE_URL}/admin/login/mfa`, {
+      method: 'POST',
+      headers: {
+        'Content-Type': 'application/json',
+      },
+      body: JSON.stringify({ mfa_token: mfaToken, code }),
+    });
+    return handleResponse(response);
+  },
+
+  refresh: async (refreshToken) => {
+    const response = await fetchWithTimeout(`${API_BASE_URL}/admin/refresh`, {
+      method: 'POST',
+      headers: {
+        'Content-Type': 'application/json',
+      },
+      body: JSON.stringify({ refresh_token: refreshToken }),
+    });
+    return handleResponse(response);
+  },
+
+  logout: async (allDevices = false) => {
+    const token = adminToken.get();
+    if (token) {
+      try {
+        await fetchWithTimeout(`${API_BASE_URL}/admin/${allDevices ? 'logout-all' : 'logout'}`, {
+          method: 'POST',
+          headers: {
+            'Authorization': `Bearer ${token}`,
+          },
+        });
+      } catch (error) {
+        console.error('Logout error:', error);
+      }
+    }
+    adminToken.remove();
+  },
+
   verifySession: async (token) => {
     try {
       const response = await fetchWithTimeout(`${API_BASE_URL}/admin/verify-session`, {
@@ -109,17 +148,7 @@ export const adminApi = {
           'Content-Type': 'application/json'
         },
       });
-      
-      if (response.status === 404) {
-        const testResponse = await fetchWithTimeout(`${API_BASE_URL}/admin/blogs`, {
-          method: 'GET',
-          headers: {
-            'Authorization': `Bearer ${token}`,
-          },
-        });
-        return { valid: testResponse.ok, adminID: 'default-admin' };
-      }
-      
+
       return handleResponse(response);
     } catch (error) {
       console.error('Session verification error:', error);
@@ -129,9 +158,12 @@ export const adminApi = {
 };
 
 export const adminToken = {
-  store: (token) => {
+  store: (token, refreshToken) => {
     if (typeof window !== 'undefined') {
       localStorage.setItem('adminToken', token);
+      if (refreshToken) {
+        localStorage.setItem('adminRefreshToken', refreshToken);
+      }
     }
   },
   get: () => {
@@ -140,16 +172,50 @@ export const adminToken = {
     }
     return null;
   },
+  getRefresh: () => {
+    if (typeof window !== 'undefined') {
+      return localStorage.getItem('adminRefreshToken');
+    }
+    return null;
+  },
   remove: () => {
     if (typeof window !== 'undefined') {
       localStorage.removeItem('adminToken');
+      localStorage.removeItem('adminRefreshToken');
     }
   }
 };
 
+// Sends an authenticated request, refreshing the access token once if it expired
+const fetchWithAuth = async (url, options = {}) => {
+  const send = (token) => fetchWithTimeout(url, {
+    ...options,
+    headers: {
+      ...(options.headers || {}),
+      'Authorization': `Bearer ${token}`,
+    },
+  });
+
+  const response = await send(adminToken.get());
+  if (response.status !== 401 || !adminToken.getRefresh()) {
+    return response;
+  }
+
+  try {
+    const tokens = await adminApi.refresh(adminToken.getRefresh());
+    adminToken.store(tokens.token, tokens.refresh_token);
+    return send(tokens.token);
+  } catch (error) {
+    adminToken.remove();
+    return response;
+  }
+};
+
 export const getImageUrl = (imagePath) => {
   if (!imagePath) return '/default-blog.jpg';
   if (imagePath.startsWith('http')) return imagePath;
+  // Generated images, such as preview cards, are served by the API itself
+  if (imagePath.startsWith('/api/')) return `${API_BASE_URL.replace(/\/api$/, '')}${imagePath}`;
   
   const cleanPath = imagePath
     .replace(/^\/+/, '')
@@ -163,8 +229,32 @@ export const getImageUrl = (imagePath) => {
   return `${API_BASE_URL.replace('/api', '')}/${finalPath}`;
 };
 
+// getFeedUrl returns the address of a published-posts feed; format is
+// "rss", "atom" or "json"
+export const getFeedUrl = (format = 'rss') =>
+  `${API_BASE_URL.replace('/api', '')}/feeds/blog.${format}`;
+
+const SEO_FIELDS = ['meta_title', 'meta_description', 'canonical_url', 'noindex'];
+
+// appendSeoFields adds the SEO overrides that were given. og_image may be
+// a File to upload or null to clear the preview image.
+const appendSeoFields = (formData, blogData) => {
+  SEO_FIELDS.forEach((field) => {
+    if (blogData[field] !== undefined) {
+      formData.append(field, String(blogData[field]));
+    }
+  });
+  if (blogData.og_image instanceof File) {
+    formData.append('og_image', blogData.og_image);
+  } else if (blogData.og_image === null) {
+    formData.append('og_image', '');
+  }
+};
+
 export const blogApi = {
-  getAllBlogs: async (requireAuth = false) => {
+  // Returns one page of blogs with its pagination metadata:
+  // { data: [...], pagination: { page, limit, total, total_pages } }
+  listBlogs: async (params = {}, requireAuth = false) => {
     const headers = {};
     const token = adminToken.get();
     
@@ -175,13 +265,136 @@ export const blogApi = {
       headers['Authorization'] = `Bearer ${token}`;
     }
     headers['Content-Type'] = 'application/json';
-    
-    try {
-      const response = await fetchWithTimeout(`${API_BASE_URL}/admin/blogs`, { headers });
-      return handleResponse(response);
-    } catch (error) {
-      throw error;
+
+    const query = new URLSearchParams(
+      Object.entries(params).filter(([, value]) => value !== undefined && value !== null && value !== '')
+    ).toString();
+
+    const response = await fetchWithTimeout(`${API_BASE_URL}/admin/blogs${query ? `?${query}` : ''}`, { headers });
+    return handleResponse(response);
+  },
+
+  getAllBlogs: async (requireAuth = false) => {
+    const result = await blogApi.listBlogs({ limit: 100 }, requireAuth);
+    return Array.isArray(result) ? result : result?.data || [];
+  },
+
+  // Admin views include drafts and posts under review
+  listAdminBlogs: async (params = {}) => {
+    const query = new URLSearchParams(
+      Object.entries(params).filter(([, value]) => value !== undefined && value !== null && value !== '')
+    ).toString();
+
+    const response = await fetchWithAuth(`${API_BASE_URL}/admin/manage/blogs${query ? `?${query}` : ''}`);
+    return handleResponse(response);
+  },
+
+  getAdminBlog: async (id) => {
+    if (!id) {
+      throw new Error('Blog post ID is required');
     }
+
+    const response = await fetchWithAuth(`${API_BASE_URL}/admin/manage/blogs/${id}`);
+    return handleResponse(response);
+  },
+
+  updateBlogStatus: async (id, status, note = '') => {
+    const response = await fetchWithAuth(`${API_BASE_URL}/admin/blogs/${id}/status`, {
+      method: 'POST',
+      headers: { 'Content-Type': 'application/json' },
+      body: JSON.stringify({ status, note }),
+    });
+    return handleResponse(response);
+  },
+
+  scheduleBlog: async (id, publishAt) => {
+    const response = await fetchWithAuth(`${API_BASE_URL}/admin/blogs/${id}/schedule`, {
+      method: 'PUT',
+      headers: { 'Content-Type': 'application/json' },
+      body: JSON.stringify({ publish_at: new Date(publishAt).toISOString() }),
+    });
+    return handleResponse(response);
+  },
+
+  unscheduleBlog: async (id) => {
+    const response = await fetchWithAuth(`${API_BASE_URL}/admin/blogs/${id}/schedule`, {
+      method: 'DELETE',
+    });
+    return handleResponse(response);
+  },
+
+  listRevisions: async (id, params = {}) => {
+    const query = new URLSearchParams(params).toString();
+    const response = await fetchWithAuth(`${API_BASE_URL}/admin/manage/blogs/${id}/revisions${query ? `?${query}` : ''}`);
+    return handleResponse(response);
+  },
+
+  diffRevisions: async (id, from, to = 'latest') => {
+    const query = new URLSearchParams({ from, to }).toString();
+    const response = await fetchWithAuth(`${API_BASE_URL}/admin/manage/blogs/${id}/revisions/diff?${query}`);
+    return handleResponse(response);
+  },
+
+  restoreRevision: async (id, number) => {
+    const response = await fetchWithAuth(`${API_BASE_URL}/admin/blogs/${id}/revisions/${number}/restore`, {
+      method: 'POST',
+    });
+    return handleResponse(response);
+  },
+
+  searchBlogs: async (q, params = {}) => {
+    const query = new URLSearchParams({ q, ...params }).toString();
+    const response = await fetchWithTimeout(`${API_BASE_URL}/blogs/search?${query}`);
+    return handleResponse(response);
+  },
+
+  listComments: async (blogId) => {
+    const response = await fetchWithTimeout(`${API_BASE_URL}/blogs/${blogId}/comments`);
+    return handleResponse(response);
+  },
+
+  // Comments go to moderation; website is the honeypot and stays empty
+  submitComment: async (blogId, { name, email, body, parentId, website = '' }) => {
+    const response = await fetchWithTimeout(`${API_BASE_URL}/blogs/${blogId}/comments`, {
+      method: 'POST',
+      headers: { 'Content-Type': 'application/json' },
+      body: JSON.stringify({ name, email, body, parent_id: parentId || null, website }),
+    });
+    return handleResponse(response);
+  },
+
+  listModerationComments: async (params = {}) => {
+    const query = new URLSearchParams(params).toString();
+    const response = await fetchWithAuth(`${API_BASE_URL}/admin/manage/comments${query ? `?${query}` : ''}`);
+    return handleResponse(response);
+  },
+
+  moderateComment: async (id, status) => {
+    const response = await fetchWithAuth(`${API_BASE_URL}/admin/comments/${id}/status`, {
+      method: 'POST',
+      headers: { 'Content-Type': 'application/json' },
+      body: JSON.stringify({ status }),
+    });
+    return handleResponse(response);
+  },
+
+  deleteComment: async (id) => {
+    const response = await fetchWithAuth(`${API_BASE_URL}/admin/comments/${id}`, {
+      method: 'DELETE',
+    });
+    return handleResponse(response);
+  },
+
+  listTags: async () => {
+    const response = await fetchWithTimeout(`${API_BASE_URL}/admin/tags`);
+    const result = await handleResponse(response);
+    return result?.data || [];
+  },
+
+  listCategories: async () => {
+    const response = await fetchWithTimeout(`${API_BASE_URL}/admin/categories`);
+    const result = await handleResponse(response);
+    return result?.data || [];
   },
 
   getBlog: async (id, requireAuth = false) => {
@@ -218,16 +431,23 @@ export const blogApi = {
       const formData = new FormData();
       formData.append('title', blogData.title);
       formData.append('content', blogData.content);
+      if (blogData.slug !== undefined) {
+        formData.append('slug', blogData.slug);
+      }
+      if (Array.isArray(blogData.tags)) {
+        formData.append('tags', blogData.tags.join(','));
+      }
+      if (Array.isArray(blogData.categories)) {
+        formData.append('categories', blogData.categories.join(','));
+      }
+      appendSeoFields(formData, blogData);
       
       if (blogData.image) {
         formData.append('image', blogData.image);
       }
 
-      const response = await fetchWithTimeout(`${API_BASE_URL}/admin/blogs`, {
+      const response = await fetchWithAuth(`${API_BASE_URL}/admin/blogs`, {
         method: 'POST',
-        headers: { 
-          'Authorization': `Bearer ${token}`,
-        },
         body: formData,
       });
       
@@ -254,16 +474,23 @@ export const blogApi = {
       const formData = new FormData();
       formData.append('title', blogData.title);
       formData.append('content', blogData.content);
+      if (blogData.slug !== undefined) {
+        formData.append('slug', blogData.slug);
+      }
+      if (Array.isArray(blogData.tags)) {
+        formData.append('tags', blogData.tags.join(','));
+      }
+      if (Array.isArray(blogData.categories)) {
+        formData.append('categories', blogData.categories.join(','));
+      }
+      appendSeoFields(formData, blogData);
       
       if (blogData.image) {
         formData.append('image', blogData.image);
       }
 
-      const response = await fetchWithTimeout(`${API_BASE_URL}/admin/blogs/${id}`, {
+      const response = await fetchWithAuth(`${API_BASE_URL}/admin/blogs/${id}`, {
         method: 'PUT',
-        headers: { 
-          'Authorization': `Bearer ${token}`,
-        },
         body: formData,
       });
       
@@ -284,10 +511,9 @@ export const blogApi = {
     }
 
     try {
-      const response = await fetchWithTimeout(`${API_BASE_URL}/admin/blogs/${id}`, {
+      const response = await fetchWithAuth(`${API_BASE_URL}/admin/blogs/${id}`, {
         method: 'DELETE',
         headers: { 
-          'Authorization': `Bearer ${token}`,
           'Content-Type': 'application/json'
         },
       });